@@ -0,0 +1,94 @@
+package main
+
+import (
+	"auto-checkin/internal/config"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+)
+
+// convertCommand 在 JSON、YAML、TOML 格式之间转换配置文件
+func convertCommand(args []string) {
+	flags := flag.NewFlagSet("convert", flag.ExitOnError)
+	force := flags.Bool("force", false, "目标文件已存在时覆盖")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "用法: auto-checkin convert [--force] <源配置文件> <目标配置文件>")
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+	if flags.NArg() != 2 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	src, dst := flags.Arg(0), flags.Arg(1)
+	format, err := config.FormatOf(dst)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if _, err := os.Stat(dst); err == nil && !*force {
+		log.Fatalf("%s 已存在，如需覆盖请使用 --force", dst)
+	} else if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Fatalf("检查目标文件失败: %v", err)
+	}
+	raw, err := config.ReadRaw(src)
+	if err != nil {
+		log.Fatalf("读取配置失败: %v", err)
+	}
+	data, err := config.Encode(format, raw)
+	if err != nil {
+		log.Fatalf("转换配置失败: %v", err)
+	}
+
+	if err := replaceVerified(src, dst, data); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("已将 %s 转换为 %s\n", src, dst)
+}
+
+// replaceVerified 先写入目标文件同目录下的临时文件，确认转换前后解析出的配置一致后再替换目标文件，
+// 失败时目标文件保持不变
+func replaceVerified(src, dst string, data []byte) error {
+	tmp, err := writeTemp(dst, data)
+	if err != nil {
+		return fmt.Errorf("写入配置失败: %v", err)
+	}
+	defer os.Remove(tmp)
+	before, err := config.Parse(src)
+	if err != nil {
+		return fmt.Errorf("解析源配置失败: %v", err)
+	}
+	after, err := config.Parse(tmp)
+	if err != nil {
+		return fmt.Errorf("解析转换后的配置失败: %v", err)
+	}
+	if !reflect.DeepEqual(before, after) {
+		return fmt.Errorf("转换后的配置与源配置不一致，未写入 %s", dst)
+	}
+	if err := os.Rename(tmp, dst); err != nil {
+		return fmt.Errorf("写入配置失败: %v", err)
+	}
+	return nil
+}
+
+// writeTemp 将数据写入目标文件同目录下的临时文件，扩展名与目标文件相同以便按格式解析
+func writeTemp(dst string, data []byte) (string, error) {
+	f, err := os.CreateTemp(filepath.Dir(dst), "."+filepath.Base(dst)+".*"+filepath.Ext(dst))
+	if err != nil {
+		return "", err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
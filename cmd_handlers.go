@@ -0,0 +1,32 @@
+package main
+
+import (
+	"auto-checkin/internal/config"
+	"auto-checkin/internal/handler"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+// listHandlersCommand 列出已注册的签到处理器及使用它们的网站
+func listHandlersCommand(args []string) {
+	fs := flag.NewFlagSet("list-handlers", flag.ExitOnError)
+	_ = fs.Parse(args)
+
+	// 配置不可用时仍然列出处理器
+	sites := make(map[string][]string)
+	if c, err := config.Parse(configFile); err == nil {
+		for _, w := range c.Websites {
+			sites[w.HandlerName()] = append(sites[w.HandlerName()], w.DisplayName())
+		}
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "处理器\t已配置的网站")
+	for _, name := range handler.HandlerNames() {
+		fmt.Fprintf(w, "%s\t%s\n", name, strings.Join(sites[name], ", "))
+	}
+	_ = w.Flush()
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"auto-checkin/internal/config"
+	"auto-checkin/internal/history"
+	"auto-checkin/internal/util"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+)
+
+// historyCommand 查询签到历史
+func historyCommand(args []string) {
+	fs := flag.NewFlagSet("history", flag.ExitOnError)
+	site := fs.String("site", "", "按网站名称过滤，如 jd")
+	account := fs.String("account", "", "按账号别名过滤")
+	from := fs.String("from", "", "起始日期（含），格式 2006-01-02")
+	to := fs.String("to", "", "结束日期（含），格式 2006-01-02")
+	_ = fs.Parse(args)
+
+	if _, err := config.Init(configFile); err != nil {
+		log.Fatalf("加载配置失败: %v", err)
+	}
+	loc := util.GetTimeLocation()
+	filter := history.Filter{Website: *site, Account: *account}
+	var err error
+	if *from != "" {
+		if filter.From, err = time.ParseInLocation(time.DateOnly, *from, loc); err != nil {
+			log.Fatalf("起始日期格式错误: %v", err)
+		}
+	}
+	if *to != "" {
+		if filter.To, err = time.ParseInLocation(time.DateOnly, *to, loc); err != nil {
+			log.Fatalf("结束日期格式错误: %v", err)
+		}
+		filter.To = filter.To.AddDate(0, 0, 1)
+	}
+
+	records, err := history.Open(config.Get().DataPath(history.FileName)).Query(filter)
+	if err != nil {
+		log.Fatalf("查询签到历史失败: %v", err)
+	}
+	if len(records) == 0 {
+		fmt.Println("没有符合条件的签到记录")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "时间\t网站\t账号\t状态\t奖励\t余额\t耗时\t错误")
+	accounts := make(map[string][]history.Record)
+	var order []string
+	for _, rec := range records {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%dms\t%s\n",
+			rec.Time.In(loc).Format(time.DateTime), rec.Website, rec.Account, rec.Status,
+			rec.Reward, rec.Balance, rec.DurationMs, rec.Error)
+		key := rec.Website + "\t" + rec.Account
+		if _, ok := accounts[key]; !ok {
+			order = append(order, key)
+		}
+		accounts[key] = append(accounts[key], rec)
+	}
+	_ = w.Flush()
+
+	// 连续签到天数以查询区间的最后一天为准
+	day := time.Now()
+	if !filter.To.IsZero() {
+		day = filter.To.AddDate(0, 0, -1)
+	}
+	fmt.Println()
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "网站\t账号\t连续签到天数")
+	for _, key := range order {
+		fmt.Fprintf(w, "%s\t%d\n", key, history.Streak(accounts[key], day, loc))
+	}
+	_ = w.Flush()
+}
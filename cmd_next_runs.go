@@ -0,0 +1,59 @@
+package main
+
+import (
+	"auto-checkin/internal/config"
+	"auto-checkin/internal/util"
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// nextRunsCommand 按配置的时区列出每个定时任务接下来的执行时间
+func nextRunsCommand(args []string) {
+	fs := flag.NewFlagSet("next-runs", flag.ExitOnError)
+	n := fs.Int("n", 5, "每个定时任务显示的次数")
+	_ = fs.Parse(args)
+
+	if _, err := config.Init(configFile); err != nil {
+		log.Fatalf("加载配置失败: %v", err)
+	}
+	cfg := config.Get()
+	if cfg.Debug {
+		fmt.Println("注意: 当前为调试模式，serve 只会立即执行一次，不会按定时规则签到")
+	}
+	loc := util.GetTimeLocation()
+	now := time.Now().In(loc)
+	for i, group := range cfg.CronGroups() {
+		if i > 0 {
+			fmt.Println()
+		}
+		names := make([]string, 0, len(group.Websites))
+		for _, w := range group.Websites {
+			names = append(names, w.DisplayName())
+		}
+		if group.Rule == "" {
+			fmt.Printf("(未配置定时规则)  (%s)\n", strings.Join(names, ", "))
+			continue
+		}
+		fmt.Printf("%s  (%s)\n", group.Rule, strings.Join(names, ", "))
+		schedule, err := cron.ParseStandard(group.Rule)
+		if err != nil {
+			fmt.Printf("  定时任务规则无效: %v\n", err)
+			continue
+		}
+		t := now
+		for range *n {
+			t = schedule.Next(t)
+			if t.IsZero() {
+				break
+			}
+			fmt.Printf("  %s %s\n", t.Format("2006-01-02 15:04:05 MST"), weekdays[t.Weekday()])
+		}
+	}
+}
+
+var weekdays = [...]string{"周日", "周一", "周二", "周三", "周四", "周五", "周六"}
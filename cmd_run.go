@@ -0,0 +1,107 @@
+package main
+
+import (
+	"auto-checkin/internal/config"
+	"auto-checkin/internal/fixture"
+	"auto-checkin/internal/logger"
+	"auto-checkin/internal/result"
+	"auto-checkin/internal/scheduler"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+)
+
+// runCommand 立即执行一次签到（或试运行），存在失败的网站时以非零状态码退出
+func runCommand(args []string) {
+	fs := flag.NewFlagSet("run", flag.ExitOnError)
+	only := fs.String("only", "", "只签到指定的服务，多个服务用逗号分隔，如 jd,quark")
+	force := fs.Bool("force", false, "忽略当天签到状态，今日已完成签到的账号也重新签到")
+	dryRun := fs.Bool("dry-run", false, "只打印每个处理器将要发送的请求，不访问网络")
+	record := fs.String("record", "", "正常签到，并将处理器收到的响应录制到指定夹具文件")
+	replay := fs.String("replay", "", "使用夹具文件中录制的响应离线执行处理器，不访问网络、不记录状态也不推送")
+	_ = fs.Parse(args)
+	if (*dryRun && *record != "") || (*dryRun && *replay != "") || (*record != "" && *replay != "") {
+		log.Fatal("--dry-run、--record 与 --replay 不能同时使用")
+	}
+
+	notify := setup()
+	defer logger.Log().Close()
+
+	websites, err := selectWebsites(config.Get().Websites, *only)
+	if err != nil {
+		log.Fatal(err)
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	var report *result.Report
+	switch {
+	case *dryRun:
+		scheduler.New(notify, nil).DryRun(ctx, websites, os.Stdout)
+		return
+	case *replay != "":
+		cassette, err := fixture.Load(*replay)
+		if err != nil {
+			log.Fatalf("读取夹具失败: %v", err)
+		}
+		replayer := fixture.NewReplayer(cassette)
+		report = scheduler.New(notify, replayer).Preview(ctx, websites)
+		replayer.Close()
+		for _, k := range replayer.Missed() {
+			fmt.Fprintf(os.Stderr, "没有录制的响应: %s\n", k)
+		}
+	case *record != "":
+		recorder := fixture.NewRecorder(nil)
+		report = scheduler.New(notify, recorder).Run(ctx, websites, *force)
+		if err := recorder.Cassette().Save(*record); err != nil {
+			log.Fatalf("保存夹具失败: %v", err)
+		}
+		fmt.Fprintf(os.Stderr, "已录制 %d 个响应到 %s\n", len(recorder.Cassette().Interactions), *record)
+	default:
+		report = scheduler.New(notify, nil).Run(ctx, websites, *force)
+	}
+	fmt.Println(report.Text())
+	if !*force && len(report.Results) > 0 && report.Count(result.StatusSkipped) == len(report.Results) {
+		fmt.Fprintln(os.Stderr, "所有网站均已跳过，如需重新签到今日已完成的账号请使用 --force")
+	}
+	if report.Count(result.StatusFailed) > 0 {
+		os.Exit(1)
+	}
+}
+
+// selectWebsites 按服务名或处理器名筛选网站，only 为空时返回全部网站
+func selectWebsites(websites []config.Website, only string) ([]config.Website, error) {
+	if strings.TrimSpace(only) == "" {
+		return websites, nil
+	}
+	wanted := make(map[string]bool)
+	for _, name := range strings.Split(only, ",") {
+		if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+			wanted[name] = false
+		}
+	}
+	var selected []config.Website
+	for _, w := range websites {
+		for _, name := range []string{w.Provider(), w.HandlerName()} {
+			if _, ok := wanted[name]; ok {
+				wanted[name] = true
+				selected = append(selected, w)
+				break
+			}
+		}
+	}
+	var missing []string
+	for name, found := range wanted {
+		if !found {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("配置中没有以下服务: %s", strings.Join(missing, ", "))
+	}
+	return selected, nil
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"auto-checkin/internal/config"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// validateCommand 校验配置文件，存在问题时以非零状态码退出
+func validateCommand(args []string) {
+	filename := configFile
+	if len(args) > 0 {
+		filename = args[0]
+	}
+	c, err := config.Load(filename)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Printf("%s 校验通过\n", filename)
+	// 缺少定时规则不影响 run 等命令，只提示 serve 无法启动
+	var schedErr *config.ValidationError
+	if errors.As(config.ValidateSchedule(c), &schedErr) {
+		fmt.Println("注意: 以下网站未配置定时规则，serve 无法启动:")
+		for _, p := range schedErr.Problems {
+			fmt.Println("  - " + p.Error())
+		}
+	}
+}
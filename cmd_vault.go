@@ -0,0 +1,82 @@
+package main
+
+import (
+	"auto-checkin/internal/config"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
+)
+
+const vaultUsage = `用法:
+  auto-checkin vault set <名称> [值]   写入凭据，省略值时从标准输入读取
+  auto-checkin vault get <名称>        读取凭据
+  auto-checkin vault list              列出凭据
+  auto-checkin vault rm <名称>         删除凭据
+
+口令通过环境变量 AUTO_CHECKIN_VAULT_PASSPHRASE（或 vault.passphrase_env 指定的变量）提供，
+也可在配置中设置 vault.key_file 使用密钥文件。`
+
+// vaultCommand 管理加密凭据库
+func vaultCommand(args []string) {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, vaultUsage)
+		os.Exit(2)
+	}
+	// 凭据库命令只需要 vault 配置，不展开引用也不校验
+	c, err := config.Parse(configFile)
+	if errors.Is(err, os.ErrNotExist) {
+		c = &config.Config{}
+	} else if err != nil {
+		log.Fatalf("加载配置失败: %v", err)
+	}
+	v, err := c.OpenVault()
+	if err != nil {
+		log.Fatalf("打开凭据库失败: %v", err)
+	}
+
+	switch {
+	case args[0] == "set" && (len(args) == 2 || len(args) == 3):
+		value := ""
+		if len(args) == 3 {
+			value = args[2]
+		} else {
+			data, err := io.ReadAll(os.Stdin)
+			if err != nil {
+				log.Fatalf("读取标准输入失败: %v", err)
+			}
+			value = strings.TrimRight(string(data), "\r\n")
+		}
+		if err := v.Set(args[1], value); err != nil {
+			log.Fatalf("写入凭据失败: %v", err)
+		}
+		if err := v.Save(); err != nil {
+			log.Fatalf("保存凭据库失败: %v", err)
+		}
+		fmt.Printf("已写入凭据 %s\n", args[1])
+	case args[0] == "get" && len(args) == 2:
+		value, err := v.Get(args[1])
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(value)
+	case args[0] == "list" && len(args) == 1:
+		for _, item := range v.List() {
+			fmt.Printf("%s\t%s\n", item.Name, item.Updated.Format(time.DateTime))
+		}
+	case args[0] == "rm" && len(args) == 2:
+		if err := v.Delete(args[1]); err != nil {
+			log.Fatal(err)
+		}
+		if err := v.Save(); err != nil {
+			log.Fatalf("保存凭据库失败: %v", err)
+		}
+		fmt.Printf("已删除凭据 %s\n", args[1])
+	default:
+		fmt.Fprintln(os.Stderr, vaultUsage)
+		os.Exit(2)
+	}
+}
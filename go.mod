@@ -3,6 +3,7 @@ module auto-checkin
 go 1.24
 
 require (
+	github.com/BurntSushi/toml v1.6.0
 	github.com/robfig/cron/v3 v3.0.1
 	gopkg.in/yaml.v2 v2.4.0
 )
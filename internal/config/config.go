@@ -1,55 +1,311 @@
 package config
 
 import (
-	"encoding/json"
 	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
 )
 
 type Website struct {
 	Name    string            `json:"name"`
+	Account string            `json:"account"` // 账号别名，同一服务配置多个账号时用于区分
 	Headers map[string]string `json:"headers"`
 	Query   map[string]string `json:"query"`
 	Body    map[string]any    `json:"body"`
 	Cookies map[string]string `json:"cookies"`
+	Timeout int               `json:"timeout"` // 单个网站签到超时时间（秒）
+	Retry   *Retry            `json:"retry"`   // 请求重试策略，为空时不重试
+	Handler string            `json:"handler"` // 签到处理器名称，为空时使用 name
+	Steps   []Step            `json:"steps"`   // 通用处理器（generic）的请求步骤
+	Cron    string            `json:"cron"`    // 网站独立的定时规则，为空时使用全局 cron
+	Jitter  *int              `json:"jitter"`  // 网站独立的随机延迟窗口（秒），为空时使用全局 jitter
 }
 
-type WeCom struct {
-	KEY string `json:"key"`
+// Step 通用签到处理器的一个请求步骤
+type Step struct {
+	Name         string            `json:"name"`
+	Method       string            `json:"method"`
+	URL          string            `json:"url"`
+	Query        map[string]string `json:"query"`
+	Headers      map[string]string `json:"headers"`
+	Body         any               `json:"body"`
+	BodyEncoding string            `json:"body_encoding"` // json、form 或 raw
+	Proxy        bool              `json:"proxy"`
+	Response     string            `json:"response"`     // 响应类型：json（默认）、any 或 text
+	Success      []Matcher         `json:"success"`      // 全部匹配时视为签到成功
+	AlreadyDone  []Matcher         `json:"already_done"` // 全部匹配时视为今日已签到
+	Failure      []Matcher         `json:"failure"`      // 全部匹配时视为签到失败
+	Message      string            `json:"message"`      // 展示信息模板
+	Reward       string            `json:"reward"`       // 奖励模板
+	Balance      string            `json:"balance"`      // 余额模板
+	Extract      map[string]string `json:"extract"`      // 变量名 -> JSON 路径，供后续步骤模板使用
 }
 
-type Telegram struct {
-	BotToken string `json:"bot_token"`
-	UID      string `json:"uid"`
-	APIHost  string `json:"api_host"`
-	ChatID   string `json:"chat_id"`
+// Matcher 响应匹配规则，path 为点分隔的 JSON 路径
+type Matcher struct {
+	Path     string `json:"path"`
+	Equals   any    `json:"equals"`
+	Contains string `json:"contains"`
+	Exists   *bool  `json:"exists"`
 }
 
-type Notifications struct {
-	WeCom    WeCom    `json:"wecom"`
-	Telegram Telegram `json:"telegram"`
+// Retry 请求重试策略
+type Retry struct {
+	MaxAttempts int      `json:"max_attempts"` // 最大尝试次数（含首次请求）
+	Backoff     int      `json:"backoff"`      // 首次重试前的等待时间（毫秒），之后按 2 倍递增
+	MaxBackoff  int      `json:"max_backoff"`  // 单次等待时间上限（毫秒）
+	Jitter      *float64 `json:"jitter"`       // 随机抖动比例，取值 0~1
+	RetryOn     []int    `json:"retry_on"`     // 需要重试的 HTTP 状态码
 }
+
 type Proxy struct {
 	Host string `json:"host"`
 	Port string `json:"port"`
 }
 type Config struct {
 	Cron          string        `json:"cron"`
+	RunTimeout    int           `json:"run_timeout"` // 整体签到任务超时时间（秒）
+	Jitter        int           `json:"jitter"`      // 签到前的随机延迟窗口（秒）
+	Timezone      string        `json:"timezone"`    // 定时任务与日期计算使用的时区，默认 Asia/Shanghai
+	Concurrency   int           `json:"concurrency"` // 同时签到的网站数上限，0 表示不限制
+	Sequential    bool          `json:"sequential"`  // 按配置顺序逐个签到
 	Debug         bool          `json:"debug"`
+	DataDir       string        `json:"data_dir"` // 签到历史等本地数据目录，默认 data
 	Websites      []Website     `json:"websites"`
-	Notifications Notifications `json:"notifications"`
+	Notifications Notifications `json:"notifications"` // 推送渠道列表
 	Proxy         Proxy         `json:"proxy"`
+	Vault         Vault         `json:"vault"`
+}
+
+const (
+	// DefaultSiteTimeout 单个网站默认签到超时时间（秒）
+	DefaultSiteTimeout = 120
+	// DefaultRunTimeout 整体签到任务默认超时时间（秒）
+	DefaultRunTimeout = 600
+	// DefaultRetryBackoff 默认首次重试等待时间（毫秒）
+	DefaultRetryBackoff = 1000
+	// DefaultRetryMaxBackoff 默认单次重试等待时间上限（毫秒）
+	DefaultRetryMaxBackoff = 30000
+	// DefaultRetryJitter 默认重试等待时间的随机抖动比例
+	DefaultRetryJitter = 0.2
+	// DefaultTimezone 默认时区
+	DefaultTimezone = "Asia/Shanghai"
+)
+
+// DefaultRetryOn 默认需要重试的 HTTP 状态码
+var DefaultRetryOn = []int{429, 500, 502, 503, 504}
+
+// current 当前生效的配置，热加载时整体原子替换
+var current atomic.Pointer[Config]
+
+func init() {
+	current.Store(&Config{})
+}
+
+// Get 返回当前生效的配置，调用方不应修改返回值
+func Get() *Config {
+	return current.Load()
+}
+
+// Set 原子替换当前生效的配置
+func Set(c *Config) {
+	current.Store(c)
+}
+
+// DisplayName 展示名称，多账号时附带账号
+func (w Website) DisplayName() string {
+	if w.Account == "" {
+		return w.Name
+	}
+	return w.Name + "(" + w.Account + ")"
+}
+
+// HandlerName 签到处理器名称
+func (w Website) HandlerName() string {
+	if w.Handler != "" {
+		return strings.ToLower(w.Handler)
+	}
+	return strings.ToLower(w.Name)
+}
+
+// Key 网站账号的唯一标识
+func (w Website) Key() string {
+	return w.Provider() + "/" + w.Account
+}
+
+// Provider 返回签到服务标识，同一服务的多个账号共享该标识
+func (w Website) Provider() string {
+	return strings.ToLower(w.Name)
+}
+
+// Clone 深拷贝网站配置，避免多个账号之间共享 map
+func (w Website) Clone() Website {
+	c := w
+	c.Headers = cloneStrings(w.Headers)
+	c.Query = cloneStrings(w.Query)
+	c.Cookies = cloneStrings(w.Cookies)
+	if w.Body != nil {
+		c.Body = cloneValue(w.Body).(map[string]any)
+	}
+	return c
+}
+
+func cloneStrings(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	c := make(map[string]string, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
+
+func cloneValue(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		c := make(map[string]any, len(t))
+		for k, item := range t {
+			c[k] = cloneValue(item)
+		}
+		return c
+	case []any:
+		c := make([]any, len(t))
+		for i, item := range t {
+			c[i] = cloneValue(item)
+		}
+		return c
+	default:
+		return v
+	}
+}
+
+// SiteTimeout 返回网站签到超时时间
+func (w Website) SiteTimeout() time.Duration {
+	if w.Timeout <= 0 {
+		return DefaultSiteTimeout * time.Second
+	}
+	return time.Duration(w.Timeout) * time.Second
 }
 
-var Cfg = &Config{}
+// CronGroup 使用同一定时规则的一组网站
+type CronGroup struct {
+	Rule     string
+	Websites []Website
+}
+
+// CronRule 返回网站生效的定时规则
+func (c *Config) CronRule(w Website) string {
+	if w.Cron != "" {
+		return w.Cron
+	}
+	return c.Cron
+}
+
+// CronGroups 按定时规则对网站分组，顺序与配置中首次出现的顺序一致
+func (c *Config) CronGroups() []CronGroup {
+	var groups []CronGroup
+	index := make(map[string]int)
+	for _, w := range c.Websites {
+		rule := c.CronRule(w)
+		i, ok := index[rule]
+		if !ok {
+			i = len(groups)
+			index[rule] = i
+			groups = append(groups, CronGroup{Rule: rule})
+		}
+		groups[i].Websites = append(groups[i].Websites, w)
+	}
+	return groups
+}
+
+// DataPath 返回数据目录下的文件路径
+func (c *Config) DataPath(name string) string {
+	dir := c.DataDir
+	if dir == "" {
+		dir = "data"
+	}
+	return filepath.Join(dir, name)
+}
+
+// JitterWindow 返回网站签到前的随机延迟窗口
+func (c *Config) JitterWindow(w Website) time.Duration {
+	if w.Jitter != nil {
+		return time.Duration(*w.Jitter) * time.Second
+	}
+	return time.Duration(c.Jitter) * time.Second
+}
+
+// maxJitter 返回所有网站中最大的随机延迟窗口
+func (c *Config) maxJitter() time.Duration {
+	var max time.Duration
+	for _, w := range c.Websites {
+		if j := c.JitterWindow(w); j > max {
+			max = j
+		}
+	}
+	return max
+}
+
+// MaxConcurrency 返回同时签到的网站数上限，n 为本次任务的网站数
+func (c *Config) MaxConcurrency(n int) int {
+	switch {
+	case c.Sequential:
+		return 1
+	case c.Concurrency > 0 && c.Concurrency < n:
+		return c.Concurrency
+	case n > 0:
+		return n
+	default:
+		return 1
+	}
+}
+
+// RunDeadline 返回整体签到任务的超时时间，未配置时在默认值基础上加上最大随机延迟
+func (c *Config) RunDeadline() time.Duration {
+	if c.RunTimeout <= 0 {
+		return DefaultRunTimeout*time.Second + c.maxJitter()
+	}
+	return time.Duration(c.RunTimeout) * time.Second
+}
 
+// Init 加载并校验配置文件，成功后替换全局配置；失败时保留原配置
 func Init(filename string) (*Config, error) {
+	c, err := Load(filename)
+	if err != nil {
+		return nil, err
+	}
+	Set(c)
+	return c, nil
+}
+
+// Load 读取、解析、展开引用并校验配置文件，不修改全局配置
+func Load(filename string) (*Config, error) {
+	c, err := Parse(filename)
+	if err != nil {
+		return nil, err
+	}
+	if err := Expand(c); err != nil {
+		return c, err
+	}
+	if err := Validate(c); err != nil {
+		return c, err
+	}
+	return c, nil
+}
+
+// Parse 读取并解析配置文件（按扩展名支持 JSON、YAML、TOML），不做校验
+func Parse(filename string) (*Config, error) {
 	data, err := os.ReadFile(filename)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := json.Unmarshal(data, Cfg); err != nil {
+	c := &Config{}
+	if err := decode(filename, data, c); err != nil {
 		return nil, err
 	}
-	return Cfg, nil
+	return c, nil
 }
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// Resolver 解析 ${KIND:参数} 形式的引用，返回引用的实际值
+type Resolver func(arg string) (string, error)
+
+// resolvers 已注册的引用解析器，键为引用类型
+var resolvers = map[string]Resolver{
+	"ENV":  resolveEnv,
+	"FILE": resolveFile,
+}
+
+// RegisterResolver 注册引用解析器
+func RegisterResolver(kind string, r Resolver) {
+	resolvers[strings.ToUpper(kind)] = r
+}
+
+// refPattern 匹配 ${ENV:NAME}、${FILE:/path} 等引用
+var refPattern = regexp.MustCompile(`\$\{([A-Za-z]+):([^}]*)\}`)
+
+func resolveEnv(name string) (string, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("环境变量 %s 未设置", name)
+	}
+	return value, nil
+}
+
+func resolveFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("读取密钥文件失败: %v", err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// Expand 展开网站配置与推送配置中的 ${ENV:...}、${FILE:...}、${VAULT:...} 等引用
+func Expand(c *Config) error {
+	var problems []Problem
+	vaultRef := vaultResolver(c)
+	fn := func(path, s string) (string, error) {
+		if !strings.Contains(s, "${") {
+			return s, nil
+		}
+		return refPattern.ReplaceAllStringFunc(s, func(ref string) string {
+			m := refPattern.FindStringSubmatch(ref)
+			resolve, ok := resolvers[strings.ToUpper(m[1])]
+			if strings.EqualFold(m[1], "VAULT") {
+				resolve, ok = vaultRef, true
+			}
+			if !ok {
+				problems = append(problems, Problem{path, fmt.Sprintf("未知的引用类型 %s", m[1])})
+				return ref
+			}
+			value, err := resolve(m[2])
+			if err != nil {
+				problems = append(problems, Problem{path, err.Error()})
+				return ref
+			}
+			return value
+		}), nil
+	}
+	v := reflect.ValueOf(c).Elem()
+	_ = walkStrings(v.FieldByName("Websites"), "websites", fn)
+	_ = walkStrings(v.FieldByName("Notifications"), "notifications", fn)
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
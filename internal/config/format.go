@@ -0,0 +1,164 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v2"
+)
+
+// 支持的配置文件格式
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+	FormatTOML = "toml"
+)
+
+// FormatOf 根据文件扩展名判断配置文件格式，忽略示例文件的 .example 后缀
+func FormatOf(filename string) (string, error) {
+	switch strings.ToLower(filepath.Ext(strings.TrimSuffix(filename, ".example"))) {
+	case ".json":
+		return FormatJSON, nil
+	case ".yaml", ".yml":
+		return FormatYAML, nil
+	case ".toml":
+		return FormatTOML, nil
+	default:
+		return "", fmt.Errorf("%s: 不支持的配置文件格式，仅支持 .json、.yaml/.yml、.toml", filename)
+	}
+}
+
+// decode 按文件格式解析配置；YAML 与 TOML 先转换为 JSON，保证字段语义与 JSON 配置一致
+func decode(filename string, data []byte, c *Config) error {
+	format, err := FormatOf(filename)
+	if err != nil {
+		return err
+	}
+	if format == FormatJSON {
+		return decodeJSON(filename, data, c)
+	}
+	raw, err := decodeRaw(filename, format, data)
+	if err != nil {
+		return err
+	}
+	jsonData, err := json.Marshal(raw)
+	if err != nil {
+		return fmt.Errorf("%s: %v", filename, err)
+	}
+	if err := json.Unmarshal(jsonData, c); err != nil {
+		return fmt.Errorf("%s: %v", filename, err)
+	}
+	return nil
+}
+
+// decodeRaw 将配置解析为通用的 map 结构
+func decodeRaw(filename, format string, data []byte) (map[string]any, error) {
+	var raw map[string]any
+	switch format {
+	case FormatJSON:
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return nil, jsonError(filename, data, err)
+		}
+	case FormatYAML:
+		var doc any
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("%s: YAML 语法错误: %v", filename, err)
+		}
+		m, ok := normalizeYAML(doc).(map[string]any)
+		if !ok && doc != nil {
+			return nil, fmt.Errorf("%s: 顶层必须是对象", filename)
+		}
+		raw = m
+	case FormatTOML:
+		if err := toml.Unmarshal(data, &raw); err != nil {
+			return nil, fmt.Errorf("%s: TOML 语法错误: %v", filename, err)
+		}
+	}
+	return raw, nil
+}
+
+// normalizeYAML 将 yaml.v2 解析出的 map[interface{}]interface{} 转换为 map[string]any
+func normalizeYAML(v any) any {
+	switch t := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]any, len(t))
+		for k, item := range t {
+			m[fmt.Sprint(k)] = normalizeYAML(item)
+		}
+		return m
+	case []interface{}:
+		for i, item := range t {
+			t[i] = normalizeYAML(item)
+		}
+		return t
+	default:
+		return v
+	}
+}
+
+// ReadRaw 读取配置文件为通用的 map 结构
+func ReadRaw(filename string) (map[string]any, error) {
+	format, err := FormatOf(filename)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return decodeRaw(filename, format, data)
+}
+
+// Encode 将通用 map 结构编码为指定格式
+func Encode(format string, raw map[string]any) ([]byte, error) {
+	switch format {
+	case FormatJSON:
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(raw); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case FormatYAML:
+		return yaml.Marshal(raw)
+	case FormatTOML:
+		var buf bytes.Buffer
+		// TOML 不支持 null，编码前去除空值
+		if err := toml.NewEncoder(&buf).Encode(dropNulls(raw)); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("不支持的配置文件格式: %s", format)
+	}
+}
+
+func dropNulls(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		m := make(map[string]any, len(t))
+		for k, item := range t {
+			if item != nil {
+				m[k] = dropNulls(item)
+			}
+		}
+		return m
+	case []any:
+		s := make([]any, 0, len(t))
+		for _, item := range t {
+			if item != nil {
+				s = append(s, dropNulls(item))
+			}
+		}
+		return s
+	default:
+		return v
+	}
+}
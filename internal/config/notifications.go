@@ -0,0 +1,140 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// Channel 推送渠道配置。type 决定渠道类型，其余字段由对应渠道解析，例如：
+//
+//	{"type": "telegram", "name": "个人", "bot_token": "...", "uid": "..."}
+type Channel struct {
+	Type     string         `json:"type"`
+	Name     string         `json:"name"`    // 展示名称，默认同 type
+	Settings map[string]any `json:",inline"` // 渠道自身的配置字段
+}
+
+// Label 渠道在日志与错误信息中的名称
+func (c Channel) Label() string {
+	if c.Name != "" {
+		return c.Name
+	}
+	return c.Type
+}
+
+// UnmarshalJSON 将 type、name 以外的字段收集到 Settings
+func (c *Channel) UnmarshalJSON(data []byte) error {
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	var ok bool
+	if c.Type, ok = raw["type"].(string); !ok {
+		return fmt.Errorf("推送渠道缺少 type 字段")
+	}
+	if name, exists := raw["name"]; exists {
+		if c.Name, ok = name.(string); !ok {
+			return fmt.Errorf("推送渠道的 name 必须是字符串")
+		}
+	}
+	delete(raw, "type")
+	delete(raw, "name")
+	c.Settings = raw
+	return nil
+}
+
+// MarshalJSON 与 UnmarshalJSON 对应，输出扁平的对象
+func (c Channel) MarshalJSON() ([]byte, error) {
+	raw := make(map[string]any, len(c.Settings)+2)
+	for k, v := range c.Settings {
+		raw[k] = v
+	}
+	raw["type"] = c.Type
+	if c.Name != "" {
+		raw["name"] = c.Name
+	}
+	return json.Marshal(raw)
+}
+
+// Decode 将渠道配置解析到 v，存在未知字段时返回错误
+func (c Channel) Decode(v any) error {
+	data, err := json.Marshal(c.Settings)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("配置错误: %v", err)
+	}
+	return nil
+}
+
+// Notifications 推送渠道列表，同一类型的渠道可以配置多个
+type Notifications []Channel
+
+// UnmarshalJSON 同时支持渠道列表与旧版按类型分组的对象：
+//
+//	{"wecom": {"key": "..."}, "telegram": {"bot_token": "...", "uid": "..."}}
+//
+// 旧版格式中必填字段（企业微信的 key，Telegram 的 bot_token 与 uid）为空的渠道视为未配置，与旧版本的行为一致
+func (n *Notifications) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		*n = nil
+		return nil
+	}
+	if data[0] == '[' {
+		var channels []Channel
+		if err := json.Unmarshal(data, &channels); err != nil {
+			return err
+		}
+		*n = channels
+		return nil
+	}
+
+	var legacy map[string]map[string]any
+	if err := json.Unmarshal(data, &legacy); err != nil {
+		return err
+	}
+	types := make([]string, 0, len(legacy))
+	for t := range legacy {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	channels := make(Notifications, 0, len(types))
+	for _, t := range types {
+		if !configured(t, legacy[t]) {
+			continue
+		}
+		channels = append(channels, Channel{Type: t, Settings: legacy[t]})
+	}
+	*n = channels
+	return nil
+}
+
+// legacyRequired 旧版格式中各渠道的必填字段，旧版本在这些字段为空时跳过该渠道
+var legacyRequired = map[string][]string{
+	"wecom":    {"key"},
+	"telegram": {"bot_token", "uid"},
+}
+
+// configured 判断旧版渠道配置是否启用：已知渠道要求必填字段均不为空，其他渠道要求存在非空字段
+func configured(typ string, settings map[string]any) bool {
+	if required, ok := legacyRequired[typ]; ok {
+		for _, key := range required {
+			if v, _ := settings[key].(string); v == "" {
+				return false
+			}
+		}
+		return true
+	}
+	for _, v := range settings {
+		if v != nil && v != "" {
+			return true
+		}
+	}
+	return false
+}
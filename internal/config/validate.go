@@ -0,0 +1,214 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// Problem 配置校验发现的问题
+type Problem struct {
+	Path    string
+	Message string
+}
+
+func (p Problem) Error() string {
+	if p.Path == "" {
+		return p.Message
+	}
+	return p.Path + ": " + p.Message
+}
+
+// ValidationError 配置校验失败，包含全部问题
+type ValidationError struct {
+	Problems []Problem
+}
+
+func (e *ValidationError) Error() string {
+	lines := make([]string, 0, len(e.Problems)+1)
+	lines = append(lines, fmt.Sprintf("配置校验失败，共 %d 个问题:", len(e.Problems)))
+	for _, p := range e.Problems {
+		lines = append(lines, "  - "+p.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Validator 配置校验函数
+type Validator func(c *Config) []Problem
+
+// validators 已注册的配置校验函数，签到处理器等模块可注册自己的校验规则
+var validators []Validator
+
+// RegisterValidator 注册配置校验函数
+func RegisterValidator(v Validator) {
+	validators = append(validators, v)
+}
+
+// placeholderPattern 示例配置中的占位值，如 YOUR_COOKIE
+var placeholderPattern = regexp.MustCompile(`\bYOUR_[A-Z0-9_]+\b`)
+
+// Validate 校验配置，返回 *ValidationError 或 nil
+func Validate(c *Config) error {
+	problems := validateBase(c)
+	for _, v := range validators {
+		problems = append(problems, v(c)...)
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return &ValidationError{Problems: problems}
+}
+
+// ValidateSchedule 校验 serve 定时签到所需的配置：非调试模式下每个网站都要有定时规则。
+// run、history 等命令不依赖定时规则，只有 serve 与热加载需要调用
+func ValidateSchedule(c *Config) error {
+	if c.Debug {
+		return nil
+	}
+	var problems []Problem
+	for i, w := range c.Websites {
+		if c.CronRule(w) == "" {
+			problems = append(problems, Problem{WebsitePath(i, w) + ".cron", "未配置定时任务规则（网站与全局 cron 均为空）"})
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return &ValidationError{Problems: problems}
+}
+
+// validateBase 校验与具体签到服务无关的通用配置
+func validateBase(c *Config) []Problem {
+	var problems []Problem
+	if c.Cron != "" {
+		if _, err := cron.ParseStandard(c.Cron); err != nil {
+			problems = append(problems, Problem{"cron", fmt.Sprintf("定时任务规则无效: %v", err)})
+		}
+	}
+	if c.Timezone != "" {
+		if _, err := time.LoadLocation(c.Timezone); err != nil {
+			problems = append(problems, Problem{"timezone", fmt.Sprintf("时区无效: %v", err)})
+		}
+	}
+	if c.RunTimeout < 0 {
+		problems = append(problems, Problem{"run_timeout", "不能为负数"})
+	}
+	if c.Concurrency < 0 {
+		problems = append(problems, Problem{"concurrency", "不能为负数"})
+	}
+	if c.Jitter < 0 {
+		problems = append(problems, Problem{"jitter", "不能为负数"})
+	}
+	if c.RunTimeout > 0 && c.RunDeadline() <= c.maxJitter() {
+		problems = append(problems, Problem{"run_timeout", "必须大于最大随机延迟 jitter"})
+	}
+	if len(c.Websites) == 0 {
+		problems = append(problems, Problem{"websites", "未配置任何签到网站"})
+	}
+
+	seen := make(map[string]int)
+	for i, w := range c.Websites {
+		path := WebsitePath(i, w)
+		if w.Name == "" {
+			problems = append(problems, Problem{path + ".name", "不能为空"})
+		}
+		if prev, ok := seen[w.Key()]; ok {
+			problems = append(problems, Problem{path, fmt.Sprintf("与 websites[%d] 重复，同一服务的多个账号请设置不同的 account", prev)})
+		} else {
+			seen[w.Key()] = i
+		}
+		if w.Cron != "" {
+			if _, err := cron.ParseStandard(w.Cron); err != nil {
+				problems = append(problems, Problem{path + ".cron", fmt.Sprintf("定时任务规则无效: %v", err)})
+			}
+		}
+		if w.Jitter != nil && *w.Jitter < 0 {
+			problems = append(problems, Problem{path + ".jitter", "不能为负数"})
+		}
+		if w.Timeout < 0 {
+			problems = append(problems, Problem{path + ".timeout", "不能为负数"})
+		}
+		if r := w.Retry; r != nil {
+			if r.MaxAttempts < 0 || r.Backoff < 0 || r.MaxBackoff < 0 {
+				problems = append(problems, Problem{path + ".retry", "不能包含负数"})
+			}
+			if r.Jitter != nil && (*r.Jitter < 0 || *r.Jitter > 1) {
+				problems = append(problems, Problem{path + ".retry.jitter", "取值范围为 0~1"})
+			}
+		}
+	}
+
+	// 网站配置使用 WebsitePath 定位，其余配置按字段路径定位
+	global := *c
+	global.Websites = nil
+	problems = append(problems, findPlaceholders(reflect.ValueOf(&global).Elem(), "")...)
+	for i := range c.Websites {
+		problems = append(problems, findPlaceholders(reflect.ValueOf(&c.Websites[i]).Elem(), WebsitePath(i, c.Websites[i]))...)
+	}
+	return problems
+}
+
+// findPlaceholders 查找仍为示例占位值的字符串，按路径排序
+func findPlaceholders(v reflect.Value, path string) []Problem {
+	var placeholders []Problem
+	_ = walkStrings(v, path, func(path, s string) (string, error) {
+		if m := placeholderPattern.FindString(s); m != "" {
+			placeholders = append(placeholders, Problem{path, fmt.Sprintf("仍为示例占位值 %s", m)})
+		}
+		return s, nil
+	})
+	sort.Slice(placeholders, func(i, j int) bool {
+		return placeholders[i].Path < placeholders[j].Path
+	})
+	return placeholders
+}
+
+// WebsitePath 网站配置在校验信息中的路径
+func WebsitePath(i int, w Website) string {
+	if w.Name == "" {
+		return fmt.Sprintf("websites[%d]", i)
+	}
+	return fmt.Sprintf("websites[%d](%s)", i, w.DisplayName())
+}
+
+// decodeJSON 解析 JSON 配置，语法或类型错误时给出行列号
+func decodeJSON(filename string, data []byte, c *Config) error {
+	if err := json.Unmarshal(data, c); err != nil {
+		return jsonError(filename, data, err)
+	}
+	return nil
+}
+
+// jsonError 为 JSON 解析错误补充行列号
+func jsonError(filename string, data []byte, err error) error {
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		line, col := position(data, syntaxErr.Offset)
+		return fmt.Errorf("%s:%d:%d: JSON 语法错误: %v", filename, line, col, syntaxErr)
+	}
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		line, col := position(data, typeErr.Offset)
+		return fmt.Errorf("%s:%d:%d: 字段 %s 类型错误: 期望 %s，实际为 %s", filename, line, col, typeErr.Field, typeErr.Type, typeErr.Value)
+	}
+	return fmt.Errorf("%s: %v", filename, err)
+}
+
+// position 将字节偏移量转换为行列号（从 1 开始）
+func position(data []byte, offset int64) (int, int) {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	before := data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	col := int(offset) - bytes.LastIndexByte(before, '\n')
+	return line, col
+}
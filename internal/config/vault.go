@@ -0,0 +1,62 @@
+package config
+
+import (
+	"auto-checkin/internal/vault"
+	"os"
+	"sync"
+)
+
+// DefaultVaultPassphraseEnv 默认读取凭据库口令的环境变量
+const DefaultVaultPassphraseEnv = "AUTO_CHECKIN_VAULT_PASSPHRASE"
+
+// Vault 加密凭据库配置
+type Vault struct {
+	File          string `json:"file"`           // 凭据库文件，默认 <data_dir>/vault.json
+	KeyFile       string `json:"key_file"`       // 密钥文件，配置后优先于口令
+	PassphraseEnv string `json:"passphrase_env"` // 读取口令的环境变量名
+}
+
+// VaultFile 返回凭据库文件路径
+func (c *Config) VaultFile() string {
+	if c.Vault.File == "" {
+		return c.DataPath("vault.json")
+	}
+	return c.Vault.File
+}
+
+// VaultExists 凭据库文件是否已创建，已创建时本地保存的会话 Cookie 等数据也使用凭据库密钥加密
+func (c *Config) VaultExists() bool {
+	_, err := os.Stat(c.VaultFile())
+	return err == nil
+}
+
+// OpenVault 按配置打开加密凭据库
+func (c *Config) OpenVault() (*vault.Vault, error) {
+	file := c.VaultFile()
+	env := c.Vault.PassphraseEnv
+	if env == "" {
+		env = DefaultVaultPassphraseEnv
+	}
+	return vault.Open(file, vault.Key{
+		Passphrase: os.Getenv(env),
+		KeyFile:    c.Vault.KeyFile,
+	})
+}
+
+// vaultResolver 返回解析 ${VAULT:name} 的解析器，首次使用时才打开凭据库
+func vaultResolver(c *Config) Resolver {
+	var (
+		once sync.Once
+		v    *vault.Vault
+		err  error
+	)
+	return func(name string) (string, error) {
+		once.Do(func() {
+			v, err = c.OpenVault()
+		})
+		if err != nil {
+			return "", err
+		}
+		return v.Get(name)
+	}
+}
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// walkStrings 遍历配置中的所有字符串值，fn 返回的新值会写回原位置
+func walkStrings(v reflect.Value, path string, fn func(path, s string) (string, error)) error {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return nil
+		}
+		return walkStrings(v.Elem(), path, fn)
+	case reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		elem := v.Elem()
+		if elem.Kind() != reflect.String {
+			return walkStrings(elem, path, fn)
+		}
+		s, err := fn(path, elem.String())
+		if err != nil {
+			return err
+		}
+		if v.CanSet() {
+			v.Set(reflect.ValueOf(s))
+		}
+	case reflect.String:
+		s, err := fn(path, v.String())
+		if err != nil {
+			return err
+		}
+		if v.CanSet() {
+			v.SetString(s)
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" {
+				continue
+			}
+			fieldPath := path
+			// inline 字段的内容与所在对象位于同一层级
+			if name != "" || opts != "inline" {
+				if name == "" {
+					name = field.Name
+				}
+				fieldPath = joinPath(path, name)
+			}
+			if err := walkStrings(v.Field(i), fieldPath, fn); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := walkStrings(v.Index(i), fmt.Sprintf("%s[%d]", path, i), fn); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			key, val := iter.Key(), iter.Value()
+			keyPath := joinPath(path, fmt.Sprint(key.Interface()))
+			if val.Kind() == reflect.Interface && !val.IsNil() {
+				val = val.Elem()
+			}
+			if val.Kind() != reflect.String {
+				if err := walkStrings(val, keyPath, fn); err != nil {
+					return err
+				}
+				continue
+			}
+			s, err := fn(keyPath, val.String())
+			if err != nil {
+				return err
+			}
+			if s != val.String() {
+				v.SetMapIndex(key, reflect.ValueOf(s).Convert(val.Type()))
+			}
+		}
+	}
+	return nil
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
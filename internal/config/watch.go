@@ -0,0 +1,43 @@
+package config
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// Watch 定期检查配置文件的修改时间与大小，文件变化或 trigger 收到信号时调用 onChange
+func Watch(ctx context.Context, filename string, interval time.Duration, trigger <-chan os.Signal, onChange func()) {
+	last, _ := fileVersion(filename)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-trigger:
+			last, _ = fileVersion(filename)
+			onChange()
+		case <-ticker.C:
+			v, err := fileVersion(filename)
+			if err != nil || v == last {
+				continue
+			}
+			last = v
+			onChange()
+		}
+	}
+}
+
+type version struct {
+	modTime time.Time
+	size    int64
+}
+
+func fileVersion(filename string) (version, error) {
+	info, err := os.Stat(filename)
+	if err != nil {
+		return version{}, err
+	}
+	return version{modTime: info.ModTime(), size: info.Size()}, nil
+}
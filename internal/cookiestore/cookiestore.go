@@ -0,0 +1,151 @@
+package cookiestore
+
+import (
+	"auto-checkin/internal/util"
+	"auto-checkin/internal/vault"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// FileName 数据目录下保存服务端下发 Cookie 的文件名
+const FileName = "cookies.json"
+
+// encryptedSuffix 使用凭据库密钥加密后的文件后缀
+const encryptedSuffix = ".enc"
+
+// vaultName 加密 Cookie 文件时使用的附加数据
+const vaultName = "cookies"
+
+// Entry 某个账号保存的服务端 Cookie
+type Entry struct {
+	SeedHash string             `json:"seed_hash"` // 保存时配置中 Cookie 的摘要，配置变化后这些 Cookie 作废
+	Cookies  []util.SavedCookie `json:"cookies"`
+	Updated  time.Time          `json:"updated"`
+}
+
+// Store 各账号会话 Cookie 的本地存储，签到后保存，下次签到时优先于配置中的 Cookie
+type Store struct {
+	path     string
+	mu       sync.Mutex
+	vault    *vault.Vault     // 不为空时使用凭据库密钥加密保存
+	accounts map[string]Entry // 账号标识 -> Cookie
+}
+
+var (
+	openedMu sync.Mutex
+	opened   = make(map[string]*Store)
+)
+
+// Open 加载 Cookie 存储，文件不存在时返回空存储。
+// v 不为空时使用凭据库密钥加密保存到 path.enc，并在保存后删除明文文件；原有的明文文件会被读取并迁移。
+// 同一进程内相同路径共享一个存储，避免并发执行的签到任务互相覆盖
+func Open(path string, v *vault.Vault) (*Store, error) {
+	openedMu.Lock()
+	defer openedMu.Unlock()
+	if s, ok := opened[path]; ok {
+		s.mu.Lock()
+		s.vault = v
+		s.mu.Unlock()
+		return s, nil
+	}
+
+	s := &Store{
+		path:     path,
+		vault:    v,
+		accounts: make(map[string]Entry),
+	}
+	data, err := s.read()
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			opened[path] = s
+			return s, nil
+		}
+		return s, err
+	}
+	if err := json.Unmarshal(data, &s.accounts); err != nil {
+		return s, err
+	}
+	opened[path] = s
+	return s, nil
+}
+
+// read 读取 Cookie 文件，加密保存时优先读取加密文件，不存在时读取待迁移的明文文件
+func (s *Store) read() ([]byte, error) {
+	if s.vault == nil {
+		return os.ReadFile(s.path)
+	}
+	data, err := os.ReadFile(s.path + encryptedSuffix)
+	if errors.Is(err, os.ErrNotExist) {
+		return os.ReadFile(s.path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.vault.Decrypt(vaultName, data)
+}
+
+// Restore 将账号保存的 Cookie 恢复到会话中。Store 为 nil 时 Restore、Put、Save 均不做任何操作
+func (s *Store) Restore(key string, session *util.Session) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	entry := s.accounts[key]
+	s.mu.Unlock()
+	session.Restore(entry.SeedHash, entry.Cookies)
+}
+
+// Put 记录账号会话当前的 Cookie
+func (s *Store) Put(key string, session *util.Session) {
+	if s == nil {
+		return
+	}
+	hash, cookies := session.Snapshot()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(cookies) == 0 {
+		delete(s.accounts, key)
+		return
+	}
+	s.accounts[key] = Entry{SeedHash: hash, Cookies: cookies, Updated: time.Now()}
+}
+
+// Save 保存到磁盘（仅所有者可读写），加密保存时删除遗留的明文文件
+func (s *Store) Save() error {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := json.MarshalIndent(s.accounts, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := s.path
+	if s.vault != nil {
+		path += encryptedSuffix
+		if data, err = s.vault.Encrypt(vaultName, data); err != nil {
+			return err
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return err
+	}
+	if s.vault != nil {
+		if err := os.Remove(s.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
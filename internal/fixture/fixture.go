@@ -0,0 +1,242 @@
+package fixture
+
+import (
+	"auto-checkin/internal/util"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Interaction 一次录制的请求与响应。请求只保存方法与不含查询参数的地址，避免将签名等敏感参数写入夹具
+type Interaction struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body"`
+}
+
+// Cassette 一组按发送顺序排列的录制记录
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Load 读取夹具文件
+func Load(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	c := &Cassette{}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("%s: 夹具文件格式错误: %v", path, err)
+	}
+	return c, nil
+}
+
+// Save 写入夹具文件
+func (c *Cassette) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// key 请求的匹配标识：方法 + 不含查询参数的地址
+func key(method string, u *url.URL) string {
+	return strings.ToUpper(method) + " " + u.Scheme + "://" + u.Host + u.Path
+}
+
+// Recorder 录制经过的每次请求与响应。作为处理器的客户端注入时包装按请求参数新建的客户端的传输层，
+// 代理、TLS 与 Cookie 设置与正常签到一致，重定向的每一跳分别录制
+type Recorder struct {
+	next     util.HTTPDoer
+	mu       sync.Mutex
+	cassette Cassette
+}
+
+// NewRecorder 创建录制客户端，next 为直接调用 Do 时使用的客户端，为空时使用 http.DefaultClient
+func NewRecorder(next util.HTTPDoer) *Recorder {
+	if next == nil {
+		next = http.DefaultClient
+	}
+	return &Recorder{next: next}
+}
+
+// Do 转发请求并记录响应
+func (r *Recorder) Do(req *http.Request) (*http.Response, error) {
+	resp, err := r.next.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	return r.record(req, resp)
+}
+
+// WrapTransport 实现 util.TransportWrapper，在传输层记录每次响应
+func (r *Recorder) WrapTransport(next http.RoundTripper) http.RoundTripper {
+	return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		resp, err := next.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		return r.record(req, resp)
+	})
+}
+
+// recordedHeaders 录制的响应头，Set-Cookie 等敏感信息不写入夹具
+var recordedHeaders = []string{"Content-Type", "Location"}
+
+// record 读取并记录响应，返回可再次读取响应体的响应
+func (r *Recorder) record(req *http.Request, resp *http.Response) (*http.Response, error) {
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	header := http.Header{}
+	for _, name := range recordedHeaders {
+		if v := resp.Header.Get(name); v != "" {
+			header.Set(name, v)
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Method: strings.ToUpper(req.Method),
+		URL:    req.URL.Scheme + "://" + req.URL.Host + req.URL.Path,
+		Status: resp.StatusCode,
+		Header: header,
+		Body:   string(body),
+	})
+	return resp, nil
+}
+
+// roundTripFunc 将函数适配为 http.RoundTripper
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Cassette 返回目前为止的录制记录
+func (r *Recorder) Cassette() *Cassette {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c := &Cassette{Interactions: make([]Interaction, len(r.cassette.Interactions))}
+	copy(c.Interactions, r.cassette.Interactions)
+	return c
+}
+
+// Replayer 在本地 httptest.Server 上回放录制的响应，不访问真实网络。
+// 相同方法与地址的请求按录制顺序依次返回对应的响应，没有可用记录时返回 501
+type Replayer struct {
+	server  *httptest.Server
+	mu      sync.Mutex
+	pending map[string][]Interaction
+	missed  []string
+}
+
+// NewReplayer 启动回放服务器，使用完毕后需调用 Close
+func NewReplayer(c *Cassette) *Replayer {
+	r := &Replayer{pending: make(map[string][]Interaction)}
+	for _, it := range c.Interactions {
+		u, err := url.Parse(it.URL)
+		if err != nil {
+			continue
+		}
+		k := key(it.Method, u)
+		r.pending[k] = append(r.pending[k], it)
+	}
+	r.server = httptest.NewServer(http.HandlerFunc(r.serve))
+	return r
+}
+
+// originHeader 转发到回放服务器时携带原始地址的请求头
+const originHeader = "X-Fixture-Origin"
+
+func (r *Replayer) serve(w http.ResponseWriter, req *http.Request) {
+	origin, err := url.Parse(req.Header.Get(originHeader))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	k := key(req.Method, origin)
+
+	r.mu.Lock()
+	queue := r.pending[k]
+	if len(queue) == 0 {
+		r.missed = append(r.missed, k)
+		r.mu.Unlock()
+		http.Error(w, "没有录制的响应: "+k, http.StatusNotImplemented)
+		return
+	}
+	it := queue[0]
+	r.pending[k] = queue[1:]
+	r.mu.Unlock()
+
+	for name, values := range it.Header {
+		for _, v := range values {
+			w.Header().Add(name, v)
+		}
+	}
+	w.WriteHeader(it.Status)
+	_, _ = io.WriteString(w, it.Body)
+}
+
+// Do 将请求转发到回放服务器，重定向的每一跳同样转发到回放服务器
+func (r *Replayer) Do(req *http.Request) (*http.Response, error) {
+	client := &http.Client{Transport: roundTripFunc(r.forward)}
+	return client.Do(req)
+}
+
+// forward 将单次请求改写到回放服务器，原始地址通过 originHeader 传递
+func (r *Replayer) forward(req *http.Request) (*http.Response, error) {
+	target, err := url.Parse(r.server.URL)
+	if err != nil {
+		return nil, err
+	}
+	out := req.Clone(req.Context())
+	out.URL.Scheme = target.Scheme
+	out.URL.Host = target.Host
+	out.Host = target.Host
+	out.RequestURI = ""
+	out.Header.Set(originHeader, req.URL.String())
+	return r.server.Client().Transport.RoundTrip(out)
+}
+
+// Missed 返回没有录制响应的请求
+func (r *Replayer) Missed() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.missed...)
+}
+
+// Unused 返回尚未回放的录制记录数
+func (r *Replayer) Unused() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for _, queue := range r.pending {
+		n += len(queue)
+	}
+	return n
+}
+
+// Close 关闭回放服务器
+func (r *Replayer) Close() {
+	r.server.Close()
+}
@@ -1,25 +1,104 @@
 package handler
 
 import (
+	cfg "auto-checkin/internal/config"
 	"auto-checkin/internal/interfaces"
-	"fmt"
+	"auto-checkin/internal/result"
+	"auto-checkin/internal/util"
+	"context"
+	"sort"
 	"strings"
 )
 
 type BaseLogic struct {
-	Content string
+	Ctx     context.Context
+	Result  *result.CheckinResult
+	Retry   *cfg.Retry
+	Client  util.HTTPDoer // 发送请求的客户端，为空时由 util.SendRequest 按请求参数新建
+	Session *util.Session // 账号会话，同一账号的多个请求共享 Cookie
 }
 
-func (b *BaseLogic) PushContent(format string, args ...any) string {
-	content := fmt.Sprintf(format, args...)
-	b.Content += "∷∷∷∷" + content + "\n"
-	return b.Content
+// SetClient 注入发送请求的客户端
+func (b *BaseLogic) SetClient(client util.HTTPDoer) {
+	b.Client = client
 }
 
+// Prepare 初始化本次签到的上下文与结果
+func (b *BaseLogic) Prepare(ctx context.Context, website cfg.Website) {
+	b.Ctx = ctx
+	b.Retry = website.Retry
+	b.Result = result.New(website.Name, website.Account)
+	b.Session = util.SessionFor(website.Key())
+	b.Session.Seed(cookieHeader(website.Headers), website.Cookies)
+}
+
+// cookieHeader 返回请求头中的 Cookie
+func cookieHeader(headers map[string]string) string {
+	for key, value := range headers {
+		if strings.EqualFold(key, "Cookie") {
+			return value
+		}
+	}
+	return ""
+}
+
+// PushContent 追加一条签到展示信息
+func (b *BaseLogic) PushContent(format string, args ...any) {
+	b.Result.Push(format, args...)
+}
+
+// SendRequest 在当前签到上下文中按网站的重试策略发送请求
+func (b *BaseLogic) SendRequest(req *util.RequestParams) (map[string]interface{}, error) {
+	if req.Context == nil {
+		req.Context = b.Ctx
+	}
+	if req.Retry == nil {
+		req.Retry = b.Retry
+	}
+	if req.Client == nil {
+		req.Client = b.Client
+	}
+	if req.Session == nil {
+		req.Session = b.Session
+	}
+	return util.SendRequest(req)
+}
+
+// Factory 签到处理器工厂，每个账号每次签到都会创建独立实例
+type Factory func() interfaces.Logic
+
 // CheckinHandlers  全局工厂，存储所有签到处理器
-var CheckinHandlers = make(map[string]interfaces.Logic)
+var CheckinHandlers = make(map[string]Factory)
 
 // RegisterCheckInHandler 注册签到处理器
-func RegisterCheckInHandler(name string, handler interfaces.Logic) {
-	CheckinHandlers[strings.ToLower(name)] = handler
+func RegisterCheckInHandler(name string, factory Factory) {
+	CheckinHandlers[strings.ToLower(name)] = factory
+}
+
+// HandlerNames 返回已注册的签到处理器名称（已排序）
+func HandlerNames() []string {
+	names := make([]string, 0, len(CheckinHandlers))
+	for name := range CheckinHandlers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// clientSetter 可注入 HTTP 客户端的处理器，嵌入 BaseLogic 即满足
+type clientSetter interface {
+	SetClient(client util.HTTPDoer)
+}
+
+// NewHandler 根据名称创建签到处理器实例，client 不为空时注入为处理器发送请求的客户端
+func NewHandler(name string, client util.HTTPDoer) (interfaces.Logic, bool) {
+	factory, ok := CheckinHandlers[strings.ToLower(name)]
+	if !ok {
+		return nil, false
+	}
+	handle := factory()
+	if s, ok := handle.(clientSetter); ok && client != nil {
+		s.SetClient(client)
+	}
+	return handle, true
 }
@@ -0,0 +1,378 @@
+package handler
+
+import (
+	cfg "auto-checkin/internal/config"
+	"auto-checkin/internal/interfaces"
+	"auto-checkin/internal/logger"
+	"auto-checkin/internal/result"
+	"auto-checkin/internal/util"
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+func init() {
+	RegisterCheckInHandler("generic", func() interfaces.Logic { return &Generic{} }) // 注册处理器
+}
+
+// Generic 通用签到处理器，按配置中的 steps 依次发送请求并匹配结果
+type Generic struct {
+	BaseLogic
+	website cfg.Website
+	vars    map[string]any
+	steps   map[string]any
+}
+
+// templateFuncs 模板中可用的辅助函数
+var templateFuncs = template.FuncMap{
+	"path": func(data any, path string) any {
+		v, _ := util.JSONPath(data, path)
+		return v
+	},
+	"now": util.GetMilliTimestamp,
+}
+
+// render 渲染模板字符串，不含模板语法时原样返回
+func (g *Generic) render(text string, resp map[string]any) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+	tpl, err := template.New("step").Funcs(templateFuncs).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("模板解析失败 %q: %v", text, err)
+	}
+	var buf bytes.Buffer
+	err = tpl.Execute(&buf, map[string]any{
+		"resp":    resp,
+		"vars":    g.vars,
+		"steps":   g.steps,
+		"account": g.website.Account,
+	})
+	if err != nil {
+		return "", fmt.Errorf("模板渲染失败 %q: %v", text, err)
+	}
+	return strings.ReplaceAll(buf.String(), "<no value>", ""), nil
+}
+
+// renderStrings 渲染 map 中的每个值
+func (g *Generic) renderStrings(values ...map[string]string) (map[string]string, error) {
+	out := make(map[string]string)
+	for _, m := range values {
+		for k, v := range m {
+			rendered, err := g.render(v, nil)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = rendered
+		}
+	}
+	return out, nil
+}
+
+// renderValue 递归渲染请求体中的字符串
+func (g *Generic) renderValue(v any) (any, error) {
+	switch t := v.(type) {
+	case string:
+		return g.render(t, nil)
+	case map[string]any:
+		out := make(map[string]any, len(t))
+		for k, item := range t {
+			rendered, err := g.renderValue(item)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = rendered
+		}
+		return out, nil
+	case []any:
+		out := make([]any, len(t))
+		for i, item := range t {
+			rendered, err := g.renderValue(item)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = rendered
+		}
+		return out, nil
+	default:
+		return v, nil
+	}
+}
+
+// buildRequest 根据步骤配置构造请求参数
+func (g *Generic) buildRequest(step cfg.Step) (*util.RequestParams, error) {
+	rawURL, err := g.render(step.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	query, err := g.renderStrings(g.website.Query, step.Query)
+	if err != nil {
+		return nil, err
+	}
+	headers, err := g.renderStrings(g.website.Headers, step.Headers)
+	if err != nil {
+		return nil, err
+	}
+	method := strings.ToUpper(step.Method)
+	if method == "" {
+		method = "GET"
+	}
+	req := &util.RequestParams{
+		Method:             method,
+		URL:                rawURL,
+		QueryParams:        query,
+		Headers:            headers,
+		InsecureSkipVerify: true,
+		Proxy:              step.Proxy,
+	}
+	switch strings.ToLower(step.Response) {
+	case "any":
+		// 响应是 JSON 时照常解析，否则视为空响应
+		req.AllowNonJSON = true
+	case "text":
+		// HTML、纯文本响应以 {"body": 响应文本} 供匹配规则与模板使用
+		req.RawBody = true
+	}
+	if step.Body == nil {
+		return req, nil
+	}
+
+	body, err := g.renderValue(step.Body)
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(step.BodyEncoding) {
+	case "form":
+		data, ok := body.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("form 编码的请求体必须是对象")
+		}
+		values, err := util.Map2UrlValues(data)
+		if err != nil {
+			return nil, err
+		}
+		req.BodyData = values
+	case "raw":
+		raw, ok := body.(string)
+		if !ok {
+			return nil, fmt.Errorf("raw 编码的请求体必须是字符串")
+		}
+		req.BodyData = raw
+	case "", "json":
+		if raw, ok := body.(string); ok {
+			req.BodyData = []byte(raw)
+		} else {
+			req.BodyData = body
+			req.BodyToJson = true
+		}
+	default:
+		return nil, fmt.Errorf("不支持的请求体编码: %s", step.BodyEncoding)
+	}
+	return req, nil
+}
+
+// matchOne 判断响应是否满足单条匹配规则
+func matchOne(resp map[string]any, m cfg.Matcher) bool {
+	value, exists := util.JSONPath(resp, m.Path)
+	if m.Exists != nil {
+		return exists == *m.Exists
+	}
+	if !exists {
+		return false
+	}
+	if m.Equals != nil {
+		return fmt.Sprint(value) == fmt.Sprint(m.Equals)
+	}
+	if m.Contains != "" {
+		return strings.Contains(fmt.Sprint(value), m.Contains)
+	}
+	return true
+}
+
+// match 判断响应是否满足全部匹配规则，未配置规则时返回 false
+func match(resp map[string]any, matchers []cfg.Matcher) bool {
+	if len(matchers) == 0 {
+		return false
+	}
+	for _, m := range matchers {
+		if !matchOne(resp, m) {
+			return false
+		}
+	}
+	return true
+}
+
+// runStep 执行一个步骤，并根据匹配规则更新签到状态
+func (g *Generic) runStep(index int, step cfg.Step) error {
+	name := step.Name
+	if name == "" {
+		name = fmt.Sprintf("step%d", index+1)
+	}
+	req, err := g.buildRequest(step)
+	if err != nil {
+		return fmt.Errorf("[%s]构造请求失败: %v", name, err)
+	}
+	logger.Log().Debugf("⌛ [%s]发送请求: %s %s", name, req.Method, req.URL)
+	resp, err := g.SendRequest(req)
+	if err != nil {
+		return fmt.Errorf("[%s]发送请求失败: %v", name, err)
+	}
+	g.steps[name] = resp
+	for key, path := range step.Extract {
+		if v, ok := util.JSONPath(resp, path); ok {
+			g.vars[key] = v
+		}
+	}
+
+	if err := g.pushTemplates(step, resp); err != nil {
+		return fmt.Errorf("[%s]%v", name, err)
+	}
+	switch {
+	case match(resp, step.Failure):
+		return fmt.Errorf("[%s]签到失败", name)
+	case match(resp, step.AlreadyDone):
+		g.Result.Status = result.StatusAlreadySigned
+	case match(resp, step.Success):
+		g.Result.Status = result.StatusSigned
+	case len(step.Success) > 0 || len(step.AlreadyDone) > 0:
+		return fmt.Errorf("[%s]响应未匹配任何成功条件", name)
+	}
+	return nil
+}
+
+// pushTemplates 渲染步骤的展示信息、奖励与余额
+func (g *Generic) pushTemplates(step cfg.Step, resp map[string]any) error {
+	if step.Message != "" {
+		msg, err := g.render(step.Message, resp)
+		if err != nil {
+			return err
+		}
+		if msg != "" {
+			g.PushContent("%s", msg)
+		}
+	}
+	if step.Reward != "" {
+		reward, err := g.render(step.Reward, resp)
+		if err != nil {
+			return err
+		}
+		g.Result.Reward = reward
+	}
+	if step.Balance != "" {
+		balance, err := g.render(step.Balance, resp)
+		if err != nil {
+			return err
+		}
+		g.Result.Balance = balance
+	}
+	return nil
+}
+
+// Validate 校验通用签到步骤配置
+func (g *Generic) Validate(website cfg.Website) []cfg.Problem {
+	if len(website.Steps) == 0 {
+		return []cfg.Problem{{Path: "steps", Message: "未配置签到步骤"}}
+	}
+	problems := stringTemplateProblems("headers", website.Headers)
+	problems = append(problems, stringTemplateProblems("query", website.Query)...)
+	for i, step := range website.Steps {
+		path := fmt.Sprintf("steps[%d]", i)
+		if step.URL == "" {
+			problems = append(problems, cfg.Problem{Path: path + ".url", Message: "不能为空"})
+		}
+		switch strings.ToLower(step.BodyEncoding) {
+		case "", "json", "form", "raw":
+		default:
+			problems = append(problems, cfg.Problem{Path: path + ".body_encoding", Message: "仅支持 json、form、raw"})
+		}
+		switch strings.ToLower(step.Response) {
+		case "", "json", "any", "text":
+		default:
+			problems = append(problems, cfg.Problem{Path: path + ".response", Message: "仅支持 json、any、text"})
+		}
+		fields := []struct{ name, text string }{
+			{"url", step.URL}, {"message", step.Message}, {"reward", step.Reward}, {"balance", step.Balance},
+		}
+		for _, f := range fields {
+			problems = append(problems, templateProblems(path+"."+f.name, f.text)...)
+		}
+		problems = append(problems, stringTemplateProblems(path+".query", step.Query)...)
+		problems = append(problems, stringTemplateProblems(path+".headers", step.Headers)...)
+		problems = append(problems, bodyTemplateProblems(path+".body", step.Body)...)
+	}
+	return problems
+}
+
+// templateProblems 校验单个模板字符串能否解析
+func templateProblems(path, text string) []cfg.Problem {
+	if _, err := template.New(path).Funcs(templateFuncs).Parse(text); err != nil {
+		return []cfg.Problem{{Path: path, Message: fmt.Sprintf("模板无效: %v", err)}}
+	}
+	return nil
+}
+
+// stringTemplateProblems 按键名顺序校验 map 中的模板
+func stringTemplateProblems(path string, values map[string]string) []cfg.Problem {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var problems []cfg.Problem
+	for _, k := range keys {
+		problems = append(problems, templateProblems(path+"."+k, values[k])...)
+	}
+	return problems
+}
+
+// bodyTemplateProblems 递归校验请求体中的模板字符串
+func bodyTemplateProblems(path string, v any) []cfg.Problem {
+	var problems []cfg.Problem
+	switch t := v.(type) {
+	case string:
+		problems = templateProblems(path, t)
+	case map[string]any:
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			problems = append(problems, bodyTemplateProblems(path+"."+k, t[k])...)
+		}
+	case []any:
+		for i, item := range t {
+			problems = append(problems, bodyTemplateProblems(fmt.Sprintf("%s[%d]", path, i), item)...)
+		}
+	}
+	return problems
+}
+
+// Run 执行签到操作
+func (g *Generic) Run(ctx context.Context, website cfg.Website) *result.CheckinResult {
+	logger.Log().Debugf("----------%s开始签到----------", website.DisplayName())
+	g.Prepare(ctx, website)
+	g.website = website
+	g.vars = make(map[string]any)
+	g.steps = make(map[string]any)
+	if len(website.Steps) == 0 {
+		g.Result.Fail(fmt.Errorf("未配置签到步骤"))
+		return g.Result.Finish()
+	}
+	for i, step := range website.Steps {
+		if err := g.runStep(i, step); err != nil {
+			logger.Log().Errorf("[%s]签到失败: %v", website.DisplayName(), err)
+			g.Result.Fail(err)
+			break
+		}
+	}
+	// 所有步骤均未给出结论时，以请求全部成功视为签到成功
+	if g.Result.Status == result.StatusUnknown {
+		g.Result.Status = result.StatusSigned
+	}
+	logger.Log().Debugf("----------%s结束签到----------", website.DisplayName())
+	return g.Result.Finish()
+}
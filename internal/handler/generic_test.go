@@ -0,0 +1,81 @@
+package handler_test
+
+import (
+	"auto-checkin/internal/config"
+	"auto-checkin/internal/handler"
+	"auto-checkin/internal/interfaces"
+	"auto-checkin/internal/result"
+	"reflect"
+	"testing"
+)
+
+func TestGenericResponseTypes(t *testing.T) {
+	text := config.Website{
+		Name:    "Example",
+		Handler: "generic",
+		Steps: []config.Step{{
+			Method:   "POST",
+			URL:      "https://example.com/checkin",
+			Response: "text",
+			Success:  []config.Matcher{{Path: "body", Contains: "签到成功"}},
+			Reward:   "{{if .resp.body}}10 积分{{end}}",
+		}},
+	}
+	lenient := config.Website{
+		Name:    "Example",
+		Handler: "generic",
+		Steps: []config.Step{
+			{URL: "https://example.com/ping", Response: "any"},
+			{
+				Method:   "POST",
+				URL:      "https://example.com/checkin",
+				Response: "any",
+				Success:  []config.Matcher{{Path: "code", Equals: 0}},
+				Reward:   "{{.resp.data.reward}}",
+			},
+		},
+	}
+	runCases(t, "generic", text, []handlerCase{
+		{name: "text", cassette: "generic_text.json", status: result.StatusSigned, reward: "10 积分"},
+	})
+	runCases(t, "generic", lenient, []handlerCase{
+		{name: "any", cassette: "generic_any.json", status: result.StatusSigned, reward: "5"},
+	})
+
+	// 默认按 JSON 解析，HTML 响应视为失败
+	text.Steps[0].Response = ""
+	if got := replay(t, "generic", text, "generic_text.json"); got.Status != result.StatusFailed {
+		t.Errorf("status = %v, want %v", got.Status, result.StatusFailed)
+	}
+}
+
+func TestGenericValidateTemplates(t *testing.T) {
+	h, _ := handler.NewHandler("generic", nil)
+	v := h.(interfaces.Validator)
+	website := config.Website{
+		Name:    "Example",
+		Handler: "generic",
+		Headers: map[string]string{"X-Token": "{{.vars.token"},
+		Steps: []config.Step{{
+			URL:      "https://example.com/checkin",
+			Query:    map[string]string{"t": "{{now}"},
+			Headers:  map[string]string{"X-Sign": "{{end}}"},
+			Body:     map[string]any{"list": []any{"ok", "{{.vars.a"}},
+			Response: "html",
+		}},
+	}
+	var got []string
+	for _, p := range v.Validate(website) {
+		got = append(got, p.Path)
+	}
+	want := []string{
+		"headers.X-Token",
+		"steps[0].response",
+		"steps[0].query.t",
+		"steps[0].headers.X-Sign",
+		"steps[0].body.list[1]",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("problems = %v, want %v", got, want)
+	}
+}
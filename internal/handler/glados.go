@@ -2,8 +2,12 @@ package handler
 
 import (
 	cfg "auto-checkin/internal/config"
+	"auto-checkin/internal/interfaces"
 	"auto-checkin/internal/logger"
+	"auto-checkin/internal/result"
 	"auto-checkin/internal/util"
+	"context"
+	"fmt"
 )
 
 type Glados struct {
@@ -12,11 +16,11 @@ type Glados struct {
 }
 
 func init() {
-	RegisterCheckInHandler("glados", &Glados{}) // 注册处理器
+	RegisterCheckInHandler("glados", func() interfaces.Logic { return &Glados{} }) // 注册处理器
 }
 
 func (i *Glados) getUserInfo() error {
-	response, err := util.SendRequest(&util.RequestParams{
+	response, err := i.SendRequest(&util.RequestParams{
 		Method:             "GET",
 		URL:                "https://glados.network/api/user/status",
 		Headers:            i.website.Headers,
@@ -28,14 +32,36 @@ func (i *Glados) getUserInfo() error {
 	}
 	if code, ok := response["code"].(float64); ok && 0 == code {
 		if data, ok := response["data"].(map[string]any); ok {
-			i.PushContent("👶 账号：%s", data["email"].(string))
+			if email, ok := data["email"].(string); ok {
+				i.PushContent("👶 账号：%s", email)
+			}
 		}
 	}
 	return nil
 }
 
+// pushBalance 记录当前 Points
+func (i *Glados) pushBalance(response map[string]any) error {
+	list, ok := response["list"].([]any)
+	if !ok || len(list) == 0 {
+		return nil
+	}
+	lp, ok := list[0].(map[string]any)
+	if !ok {
+		return nil
+	}
+	raw, _ := lp["balance"].(string)
+	balance, err := util.StringToInt(raw)
+	if err != nil {
+		return err
+	}
+	i.Result.Balance = fmt.Sprintf("%d Points", balance)
+	i.PushContent("🎁 当前Points: %d", balance)
+	return nil
+}
+
 func (i *Glados) doSign() error {
-	response, err := util.SendRequest(&util.RequestParams{
+	response, err := i.SendRequest(&util.RequestParams{
 		Method:             "POST",
 		URL:                "https://glados.network/api/user/checkin",
 		Headers:            i.website.Headers,
@@ -47,67 +73,43 @@ func (i *Glados) doSign() error {
 	if err != nil {
 		return err
 	}
-	if code, ok := response["code"].(float64); ok {
-		if 0 == code {
-			if msg, ok := response["message"].(string); ok {
-				i.PushContent("💾 %s", msg)
-			}
-			if list, ok := response["list"].([]any); ok {
-				if list != nil {
-					for idx, v := range list {
-						lp := v.(map[string]any)
-						if idx == 0 {
-							balance, err := util.StringToInt(lp["balance"].(string))
-							if err != nil {
-								return err
-							}
-							i.PushContent("🎁 当前Points: %d", balance)
-						}
-					}
-				}
-			}
-		} else {
-			if msg, ok := response["message"].(string); ok {
-				i.PushContent("🔔 %s", msg)
-			}
-			if list, ok := response["list"].([]any); ok {
-				if list != nil {
-					for idx, v := range list {
-						lp := v.(map[string]any)
-						if idx == 0 {
-							balance, err := util.StringToInt(lp["balance"].(string))
-							if err != nil {
-								return err
-							}
-							i.PushContent("🎁 当前Points: %d", balance)
-						}
-					}
-				}
-			}
-		}
+	code, ok := response["code"].(float64)
+	if !ok {
+		return fmt.Errorf("无效的响应: %v", response)
 	}
-	return nil
+	msg, _ := response["message"].(string)
+	switch code {
+	case 0:
+		i.Result.Status = result.StatusSigned
+		i.Result.Reward = msg
+		i.PushContent("💾 %s", msg)
+	case 1:
+		// code 为 1 表示今日已签到（Please Try Tomorrow）
+		i.Result.Status = result.StatusAlreadySigned
+		i.PushContent("🔔 %s", msg)
+	default:
+		i.PushContent("🔔 %s", msg)
+		return fmt.Errorf("签到失败: %s", msg)
+	}
+	return i.pushBalance(response)
 }
 
-// NewGlados 初始化 Quark 实例
-func NewGlados(website cfg.Website) *Glados {
-	obj := &Glados{
-		website: website,
-	}
-	obj.Content = "👙 [服务]" + website.Name + "签到信息\n"
-	return obj
+// Validate 校验 Glados 签到配置
+func (i *Glados) Validate(website cfg.Website) []cfg.Problem {
+	return requireCookie(website)
 }
 
 // Run 执行签到操作
-func (i *Glados) Run(website cfg.Website) string {
+func (i *Glados) Run(ctx context.Context, website cfg.Website) *result.CheckinResult {
 	logger.Log().Debug("----------Glados开始签到----------")
-	glados := NewGlados(website)
-	_ = glados.getUserInfo()
-	err := glados.doSign()
+	i.Prepare(ctx, website)
+	i.website = website
+	_ = i.getUserInfo()
+	err := i.doSign()
 	if err != nil {
 		logger.Log().Error("[Glados]签到失败: " + err.Error())
-		return ""
+		i.Result.Fail(err)
 	}
 	logger.Log().Debug("----------Glados结束签到----------")
-	return glados.Content
+	return i.Result.Finish()
 }
@@ -0,0 +1,20 @@
+package handler_test
+
+import (
+	"auto-checkin/internal/config"
+	"auto-checkin/internal/result"
+	"testing"
+)
+
+func TestGlados(t *testing.T) {
+	website := config.Website{
+		Name:    "Glados",
+		Headers: map[string]string{"Cookie": "test"},
+		Body:    map[string]any{"token": "glados.one"},
+	}
+	runCases(t, "glados", website, []handlerCase{
+		{name: "success", cassette: "glados_success.json", status: result.StatusSigned, reward: "Checkin! Got 1 Points", balance: "101 Points"},
+		{name: "already_signed", cassette: "glados_already_signed.json", status: result.StatusAlreadySigned, balance: "101 Points"},
+		{name: "failure", cassette: "glados_failure.json", status: result.StatusFailed},
+	})
+}
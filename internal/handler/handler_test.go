@@ -0,0 +1,65 @@
+package handler_test
+
+import (
+	"auto-checkin/internal/config"
+	"auto-checkin/internal/fixture"
+	"auto-checkin/internal/handler"
+	"auto-checkin/internal/result"
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+// handlerCase 使用录制的响应回放一次签到，并校验结果
+type handlerCase struct {
+	name     string
+	cassette string // testdata 下的夹具文件
+	status   result.Status
+	reward   string
+	balance  string
+}
+
+// runCases 依次回放每个夹具并校验状态、奖励与余额
+func runCases(t *testing.T, handlerName string, website config.Website, cases []handlerCase) {
+	t.Helper()
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := website
+			w.Account = tc.name // 每个用例使用独立的账号会话
+			got := replay(t, handlerName, w, tc.cassette)
+			if got.Status != tc.status {
+				t.Errorf("status = %v, want %v (errors: %v)", got.Status, tc.status, got.Err())
+			}
+			if got.Reward != tc.reward {
+				t.Errorf("reward = %q, want %q", got.Reward, tc.reward)
+			}
+			if got.Balance != tc.balance {
+				t.Errorf("balance = %q, want %q", got.Balance, tc.balance)
+			}
+		})
+	}
+}
+
+// replay 在本地 httptest 服务器上回放夹具并执行处理器，要求每个请求都有录制的响应且录制的响应全部用完
+func replay(t *testing.T, handlerName string, website config.Website, cassette string) *result.CheckinResult {
+	t.Helper()
+	c, err := fixture.Load(filepath.Join("testdata", cassette))
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayer := fixture.NewReplayer(c)
+	defer replayer.Close()
+
+	h, ok := handler.NewHandler(handlerName, replayer)
+	if !ok {
+		t.Fatalf("未注册的处理器 %q", handlerName)
+	}
+	res := h.Run(context.Background(), website)
+	if missed := replayer.Missed(); len(missed) > 0 {
+		t.Errorf("没有录制的响应: %v", missed)
+	}
+	if n := replayer.Unused(); n > 0 {
+		t.Errorf("%d 个录制的响应未被使用", n)
+	}
+	return res
+}
@@ -2,8 +2,13 @@ package handler
 
 import (
 	cfg "auto-checkin/internal/config"
+	"auto-checkin/internal/interfaces"
 	"auto-checkin/internal/logger"
+	"auto-checkin/internal/result"
 	"auto-checkin/internal/util"
+	"context"
+	"fmt"
+	"strings"
 )
 
 type Ikuuu struct {
@@ -12,11 +17,11 @@ type Ikuuu struct {
 }
 
 func init() {
-	RegisterCheckInHandler("ikuuu", &Ikuuu{}) // 注册处理器
+	RegisterCheckInHandler("ikuuu", func() interfaces.Logic { return &Ikuuu{} }) // 注册处理器
 }
 
 func (i *Ikuuu) doSign() error {
-	response, err := util.SendRequest(&util.RequestParams{
+	response, err := i.SendRequest(&util.RequestParams{
 		Method:             "POST",
 		URL:                "https://ikuuu.de/user/checkin",
 		Headers:            i.Headers,
@@ -25,28 +30,35 @@ func (i *Ikuuu) doSign() error {
 	if err != nil {
 		return err
 	}
-	i.PushContent("💾 %s", response["msg"].(string))
-	return nil
+	msg, _ := response["msg"].(string)
+	i.PushContent("💾 %s", msg)
+	if ret, ok := response["ret"].(float64); ok && ret == 1 {
+		i.Result.Status = result.StatusSigned
+		i.Result.Reward = msg
+		return nil
+	}
+	if strings.Contains(msg, "已经签到") {
+		i.Result.Status = result.StatusAlreadySigned
+		return nil
+	}
+	return fmt.Errorf("签到失败: %s", msg)
 }
 
-// NewIkuuu 初始化 Quark 实例
-func NewIkuuu(website cfg.Website) *Ikuuu {
-	obj := &Ikuuu{
-		Headers: website.Headers,
-	}
-	obj.Content = "👙 [服务]" + website.Name + "签到信息\n"
-	return obj
+// Validate 校验 iKuuu 签到配置
+func (i *Ikuuu) Validate(website cfg.Website) []cfg.Problem {
+	return requireCookie(website)
 }
 
 // Run 执行签到操作
-func (i *Ikuuu) Run(website cfg.Website) string {
+func (i *Ikuuu) Run(ctx context.Context, website cfg.Website) *result.CheckinResult {
 	logger.Log().Debug("----------IKuuu开始签到----------")
-	ikuuu := NewIkuuu(website)
-	err := ikuuu.doSign()
+	i.Prepare(ctx, website)
+	i.Headers = website.Headers
+	err := i.doSign()
 	if err != nil {
 		logger.Log().Error("[ikuuu]签到失败: " + err.Error())
-		return ""
+		i.Result.Fail(err)
 	}
 	logger.Log().Debug("----------IKuuu结束签到----------")
-	return ikuuu.Content
+	return i.Result.Finish()
 }
@@ -0,0 +1,19 @@
+package handler_test
+
+import (
+	"auto-checkin/internal/config"
+	"auto-checkin/internal/result"
+	"testing"
+)
+
+func TestIkuuu(t *testing.T) {
+	website := config.Website{
+		Name:    "IKUUU",
+		Headers: map[string]string{"Cookie": "test"},
+	}
+	runCases(t, "ikuuu", website, []handlerCase{
+		{name: "success", cassette: "ikuuu_success.json", status: result.StatusSigned, reward: "你获得了 1024MB流量"},
+		{name: "already_signed", cassette: "ikuuu_already_signed.json", status: result.StatusAlreadySigned},
+		{name: "failure", cassette: "ikuuu_failure.json", status: result.StatusFailed},
+	})
+}
@@ -2,14 +2,18 @@ package handler
 
 import (
 	cfg "auto-checkin/internal/config"
+	"auto-checkin/internal/interfaces"
 	"auto-checkin/internal/logger"
+	"auto-checkin/internal/result"
 	"auto-checkin/internal/util"
+	"context"
 	"fmt"
 	"net/url"
+	"strings"
 )
 
 func init() {
-	RegisterCheckInHandler("jd", &JD{}) // 注册处理器
+	RegisterCheckInHandler("jd", func() interfaces.Logic { return &JD{} }) // 注册处理器
 }
 
 // JD 封装京东签到逻辑
@@ -38,15 +42,18 @@ func (j *JD) balance() error {
 		InsecureSkipVerify: true,
 	}
 	logger.Log().Debug("⌛ 准备发送BEAN_BALANCE请求")
-	response, err := util.SendRequest(reqParams)
+	response, err := j.SendRequest(reqParams)
 	if err != nil {
 		logger.Log().Error("❌ 发送请求失败")
 		return fmt.Errorf("❌ 发送请求失败: %v", err)
 	}
 	if code, ok := response["code"].(string); ok && "0000" == code {
 		if data, ok := response["data"].(map[string]any); ok && data != nil {
-			j.PushContent("🍅 京豆余额:%.f", data["balance"].(float64))
-			return nil
+			if balance, ok := data["balance"].(float64); ok {
+				j.Result.Balance = fmt.Sprintf("%.f 京豆", balance)
+				j.PushContent("🍅 京豆余额:%.f", balance)
+				return nil
+			}
 		}
 	}
 	j.PushContent("🍅 京豆余额:获取失败")
@@ -58,8 +65,7 @@ func (j *JD) doSign() error {
 	// 构造请求参数
 	values, err := util.Map2UrlValues(j.website.Body)
 	if err != nil {
-		j.PushContent("❌ [doSign]构造请求参数失败")
-		return err
+		return fmt.Errorf("构造请求参数失败: %v", err)
 	}
 
 	reqParams := &util.RequestParams{
@@ -69,31 +75,36 @@ func (j *JD) doSign() error {
 		Headers:            j.website.Headers,
 		InsecureSkipVerify: true,
 	}
-	response, err := util.SendRequest(reqParams)
+	response, err := j.SendRequest(reqParams)
 	if err != nil {
-		j.PushContent("❌ 发送请求失败")
-		return fmt.Errorf("❌ 发送请求失败: %v", err)
+		return fmt.Errorf("发送请求失败: %v", err)
 	}
 
 	// 处理签到结果
 	if success, ok := response["success"].(bool); ok && success == true {
-		responseData := response["data"].(map[string]any)
+		responseData, _ := response["data"].(map[string]any)
 		if assignmentInfo, ok := responseData["assignmentInfo"].(map[string]any); ok && assignmentInfo != nil {
-			j.PushContent("📋 累计签到次数:%d", assignmentInfo["completionCnt"].(int))
-			j.PushContent("📋 连续签到次数:%d", assignmentInfo["continueSignDay"].(int))
+			j.PushContent("📋 累计签到次数:%.f", assignmentInfo["completionCnt"])
+			j.PushContent("📋 连续签到次数:%.f", assignmentInfo["continueSignDay"])
 		}
 		if assignmentRewardInfo, ok := responseData["assignmentRewardInfo"].(map[string]any); ok && assignmentRewardInfo != nil {
-			if jingDouRewards, ok := responseData["jingDouRewards"].([]map[string]any); ok && jingDouRewards != nil {
-				for _, reward := range jingDouRewards {
-					j.PushContent("🏆 签到奖励:%s", reward["rewardName"].(string))
+			if jingDouRewards, ok := assignmentRewardInfo["jingDouRewards"].([]any); ok && jingDouRewards != nil {
+				var rewards []string
+				for _, item := range jingDouRewards {
+					if reward, ok := item.(map[string]any); ok {
+						name, _ := reward["rewardName"].(string)
+						rewards = append(rewards, name)
+						j.PushContent("🏆 签到奖励:%s", name)
+					}
 				}
+				j.Result.Reward = strings.Join(rewards, ", ")
 			}
 		}
-		j.PushContent("✅ 京东签到成功")
+		j.Result.Status = result.StatusSigned
 		return nil
 	} else {
 		if errCode, ok := response["errCode"].(string); ok && errCode == "302" {
-			j.PushContent("✅ 京东已完成签到")
+			j.Result.Status = result.StatusAlreadySigned
 			return nil
 		} else {
 			if errMessage, ok := response["errMessage"].(string); ok {
@@ -101,32 +112,29 @@ func (j *JD) doSign() error {
 			}
 		}
 	}
-	j.PushContent("❌ 京东签到失败")
-	return fmt.Errorf("❌ 京东签到失败: %v", response["message"])
+	return fmt.Errorf("京东签到失败: %v", response["message"])
 }
 
-// NewJD 初始化 JD 实例
-func NewJD(website cfg.Website) *JD {
-	website.Body["t"] = util.GetMilliTimestamp()
-	obj := &JD{
-		website: website,
-	}
-	obj.Content = "👙 [服务]" + website.Name + "签到信息\n"
-	return obj
+// Validate 校验京东签到配置
+func (j *JD) Validate(website cfg.Website) []cfg.Problem {
+	return append(requireCookie(website), requireBody(website, "appid", "client")...)
 }
 
-func (j *JD) Run(website cfg.Website) string {
+func (j *JD) Run(ctx context.Context, website cfg.Website) *result.CheckinResult {
 	logger.Log().Debug("----------京东开始签到----------")
 	// 执行签到
-	jd := NewJD(website)
-	if jd == nil {
-		logger.Log().Error("❌ 京东初始化失败")
-		return "❌ 京东初始化失败"
+	j.Prepare(ctx, website)
+	if website.Body == nil {
+		website.Body = map[string]any{}
 	}
-	_ = jd.balance()
-	res := jd.doSign()
+	website.Body["t"] = util.GetMilliTimestamp()
+	j.website = website
+	_ = j.balance()
+	res := j.doSign()
 	if res != nil {
 		logger.Log().Error("签到失败: " + res.Error())
+		j.Result.Fail(res)
 	}
-	return jd.Content
+	logger.Log().Debug("----------京东结束签到----------")
+	return j.Result.Finish()
 }
@@ -0,0 +1,20 @@
+package handler_test
+
+import (
+	"auto-checkin/internal/config"
+	"auto-checkin/internal/result"
+	"testing"
+)
+
+func TestJD(t *testing.T) {
+	website := config.Website{
+		Name:    "JD",
+		Headers: map[string]string{"Cookie": "pt_key=test; pt_pin=test"},
+		Body:    map[string]any{"appid": "signed_wh5", "client": "android", "functionId": "signBeanAct"},
+	}
+	runCases(t, "jd", website, []handlerCase{
+		{name: "success", cassette: "jd_success.json", status: result.StatusSigned, reward: "5京豆, 1京豆", balance: "1234 京豆"},
+		{name: "already_signed", cassette: "jd_already_signed.json", status: result.StatusAlreadySigned, balance: "1234 京豆"},
+		{name: "failure", cassette: "jd_failure.json", status: result.StatusFailed},
+	})
+}
@@ -2,14 +2,17 @@ package handler
 
 import (
 	cfg "auto-checkin/internal/config"
+	"auto-checkin/internal/interfaces"
 	"auto-checkin/internal/logger"
+	"auto-checkin/internal/result"
 	"auto-checkin/internal/util"
+	"context"
 	"encoding/json"
 	"fmt"
 )
 
 func init() {
-	RegisterCheckInHandler("quark", &Quark{}) // 注册处理器
+	RegisterCheckInHandler("quark", func() interfaces.Logic { return &Quark{} }) // 注册处理器
 }
 
 // Quark 封装夸克签到逻辑
@@ -32,7 +35,7 @@ func (q *Quark) convertBytes(b int64) string {
 
 // getUserInfo 获取用户信息
 func (q *Quark) getUserInfo() map[string]interface{} {
-	result, err := util.SendRequest(&util.RequestParams{
+	result, err := q.SendRequest(&util.RequestParams{
 		Method: "GET",
 		URL:    "https://pan.quark.cn/account/info",
 		QueryParams: map[string]string{
@@ -53,7 +56,7 @@ func (q *Quark) getUserInfo() map[string]interface{} {
 
 // getGrowthInfo 获取用户当前的签到信息
 func (q *Quark) getGrowthInfo() (map[string]interface{}, error) {
-	result, err := util.SendRequest(&util.RequestParams{
+	result, err := q.SendRequest(&util.RequestParams{
 		Method:             "GET",
 		URL:                "https://drive-m.quark.cn/1/clouddrive/capacity/growth/info",
 		QueryParams:        q.website.Query,
@@ -76,7 +79,7 @@ func (q *Quark) getGrowthSign() (bool, string, error) {
 	if err != nil {
 		return false, "", err
 	}
-	response, err := util.SendRequest(&util.RequestParams{
+	response, err := q.SendRequest(&util.RequestParams{
 		Method:             "POST",
 		URL:                "https://drive-m.quark.cn/1/clouddrive/capacity/growth/sign",
 		QueryParams:        q.website.Query,
@@ -89,10 +92,14 @@ func (q *Quark) getGrowthSign() (bool, string, error) {
 	}
 
 	if data, ok := response["data"].(map[string]interface{}); ok {
-		reward := data["sign_daily_reward"].(float64)
+		reward, ok := data["sign_daily_reward"].(float64)
+		if !ok {
+			return false, "", fmt.Errorf("签到响应缺少奖励信息: %v", data["sign_daily_reward"])
+		}
 		return true, q.convertBytes(int64(reward)), nil
 	}
-	return false, response["message"].(string), nil
+	message, _ := response["message"].(string)
+	return false, message, nil
 }
 
 // DoSign 执行签到任务
@@ -104,25 +111,25 @@ func (q *Quark) doSign() error {
 	// 获取签到信息
 	growthInfo, err := q.getGrowthInfo()
 	if err != nil {
-		q.PushContent("❌ 获取成长信息失败")
-		return fmt.Errorf("❌ 获取成长信息失败: %v", err)
+		return fmt.Errorf("获取成长信息失败: %v", err)
 	}
 	// 记录用户信息
 	isVIP := "普通用户"
-	if growthInfo["88VIP"].(bool) {
+	if vip, _ := growthInfo["88VIP"].(bool); vip {
 		isVIP = "88VIP"
-	} else if growthInfo["super_vip_exp_at"].(float64) > 0 {
+	} else if exp, _ := growthInfo["super_vip_exp_at"].(float64); exp > 0 {
 		isVIP = "SVIP"
 	}
 	// 昵称兼容显示
-	nickname := userinfo["nickname"].(string)
+	nickname, _ := userinfo["nickname"].(string)
 	if nickname == "" {
 		nickname = "查询失败"
 	}
 	q.PushContent("👶 用户名: %s[%s]", nickname, isVIP)
 	// 记录容量信息
-	totalCapacity := growthInfo["total_capacity"].(float64)
-	q.PushContent("💾 网盘总容量: %s，", q.convertBytes(int64(totalCapacity)))
+	totalCapacity, _ := growthInfo["total_capacity"].(float64)
+	q.Result.Balance = q.convertBytes(int64(totalCapacity))
+	q.PushContent("💾 网盘总容量: %s，", q.Result.Balance)
 
 	if capComp, ok := growthInfo["cap_composition"].(map[string]interface{}); ok {
 		if reward, ok := capComp["sign_reward"].(float64); ok {
@@ -133,46 +140,61 @@ func (q *Quark) doSign() error {
 	}
 	// 检查是否已签到
 	if capSign, ok := growthInfo["cap_sign"].(map[string]interface{}); ok {
-		if signed := capSign["sign_daily"].(bool); signed {
-			reward := capSign["sign_daily_reward"].(float64)
-			progress := capSign["sign_progress"].(float64)
-			target := capSign["sign_target"].(float64)
-			q.PushContent("✅ 签到日志: 今日已签到+%s，连签进度(%.0f/%.0f)", q.convertBytes(int64(reward)), progress, target)
+		signed, ok := capSign["sign_daily"].(bool)
+		if !ok {
+			return fmt.Errorf("签到状态格式异常: sign_daily=%v", capSign["sign_daily"])
+		}
+		// 连签进度仅用于展示，缺失时不影响签到
+		progress, okProgress := capSign["sign_progress"].(float64)
+		target, okTarget := capSign["sign_target"].(float64)
+		streak := func(done float64) string {
+			if !okProgress || !okTarget {
+				return ""
+			}
+			return fmt.Sprintf("，连签进度(%.0f/%.0f)", done, target)
+		}
+		if signed {
+			reward, ok := capSign["sign_daily_reward"].(float64)
+			if !ok {
+				return fmt.Errorf("签到奖励格式异常: sign_daily_reward=%v", capSign["sign_daily_reward"])
+			}
+			q.Result.Status = result.StatusAlreadySigned
+			q.Result.Reward = "+" + q.convertBytes(int64(reward))
+			q.PushContent("✅ 签到日志: 今日已签到+%s%s", q.convertBytes(int64(reward)), streak(progress))
 		} else {
 			success, reward, err := q.getGrowthSign()
 			if err != nil {
-				q.PushContent("❌ 签到异常")
 				logger.Log().Errorf("❌ 签到异常: %v", err)
+				return fmt.Errorf("签到异常: %v", err)
 			} else if success {
-				progress := capSign["sign_progress"].(float64) + 1
-				target := capSign["sign_target"].(float64)
-				q.PushContent("✅ 执行签到: 今日签到+%s，连签进度(%.0f/%.0f)", reward, progress, target)
+				q.Result.Status = result.StatusSigned
+				q.Result.Reward = "+" + reward
+				q.PushContent("✅ 执行签到: 今日签到+%s%s", reward, streak(progress+1))
 			} else {
-				q.PushContent("❌ 签到异常")
 				logger.Log().Errorf("❌ 签到异常: %s\n", reward)
+				return fmt.Errorf("签到异常: %s", reward)
 			}
 		}
+		return nil
 	}
-	return nil
+	return fmt.Errorf("未获取到签到状态")
 }
 
-// NewQuark 初始化 Quark 实例
-func NewQuark(website cfg.Website) *Quark {
-	obj := &Quark{
-		website: website,
-	}
-	obj.Content = "👙 [服务]" + website.Name + "签到信息\n"
-	return obj
+// Validate 校验夸克签到配置
+func (q *Quark) Validate(website cfg.Website) []cfg.Problem {
+	return requireQuery(website, "kps", "sign", "vcode")
 }
 
-func (q *Quark) Run(website cfg.Website) string {
+func (q *Quark) Run(ctx context.Context, website cfg.Website) *result.CheckinResult {
 	logger.Log().Debug("----------夸克网盘开始签到----------")
 	// 执行签到
-	quark := NewQuark(website)
-	res := quark.doSign()
+	q.Prepare(ctx, website)
+	q.website = website
+	res := q.doSign()
 	if res != nil {
 		logger.Log().Error("签到失败: " + res.Error())
+		q.Result.Fail(res)
 	}
 	logger.Log().Debug("----------夸克网盘签到完毕----------")
-	return quark.Content
+	return q.Result.Finish()
 }
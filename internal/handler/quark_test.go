@@ -0,0 +1,23 @@
+package handler_test
+
+import (
+	"auto-checkin/internal/config"
+	"auto-checkin/internal/result"
+	"testing"
+)
+
+func TestQuark(t *testing.T) {
+	website := config.Website{
+		Name:    "Quark",
+		Headers: map[string]string{"Cookie": "test"},
+		Query:   map[string]string{"pr": "ucpro", "fr": "android", "kps": "kps", "sign": "sign", "vcode": "vcode"},
+		Body:    map[string]any{"sign_cyclic": true},
+	}
+	runCases(t, "quark", website, []handlerCase{
+		{name: "success", cassette: "quark_success.json", status: result.StatusSigned, reward: "+20.00 MB", balance: "10.00 TB"},
+		{name: "already_signed", cassette: "quark_already_signed.json", status: result.StatusAlreadySigned, reward: "+20.00 MB", balance: "10.00 TB"},
+		{name: "failure", cassette: "quark_failure.json", status: result.StatusFailed, balance: "10.00 TB"},
+		// 响应字段类型与预期不符时记为失败而不是 panic
+		{name: "malformed", cassette: "quark_malformed.json", status: result.StatusFailed, balance: "10.00 TB"},
+	})
+}
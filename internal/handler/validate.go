@@ -0,0 +1,73 @@
+package handler
+
+import (
+	cfg "auto-checkin/internal/config"
+	"auto-checkin/internal/interfaces"
+	"fmt"
+	"strings"
+)
+
+func init() {
+	cfg.RegisterValidator(validateWebsites)
+}
+
+// validateWebsites 校验网站是否有对应的签到处理器，并执行处理器自身的校验
+func validateWebsites(c *cfg.Config) []cfg.Problem {
+	var problems []cfg.Problem
+	for i, w := range c.Websites {
+		if w.Name == "" {
+			continue
+		}
+		path := cfg.WebsitePath(i, w)
+		handle, ok := NewHandler(w.HandlerName(), nil)
+		if !ok {
+			problems = append(problems, cfg.Problem{
+				Path:    path,
+				Message: fmt.Sprintf("不支持的签到服务 %q，可用: %s", w.HandlerName(), strings.Join(HandlerNames(), ", ")),
+			})
+			continue
+		}
+		if v, ok := handle.(interfaces.Validator); ok {
+			for _, p := range v.Validate(w) {
+				p.Path = path + "." + p.Path
+				problems = append(problems, p)
+			}
+		}
+	}
+	return problems
+}
+
+// requireBody 校验请求体中的必填字段
+func requireBody(w cfg.Website, keys ...string) []cfg.Problem {
+	var problems []cfg.Problem
+	for _, key := range keys {
+		if v, ok := w.Body[key].(string); !ok || v == "" {
+			problems = append(problems, cfg.Problem{Path: "body." + key, Message: "缺少必填字段"})
+		}
+	}
+	return problems
+}
+
+// requireQuery 校验查询参数中的必填字段
+func requireQuery(w cfg.Website, keys ...string) []cfg.Problem {
+	var problems []cfg.Problem
+	for _, key := range keys {
+		if w.Query[key] == "" {
+			problems = append(problems, cfg.Problem{Path: "query." + key, Message: "缺少必填字段"})
+		}
+	}
+	return problems
+}
+
+// requireCookie 校验是否配置了 Cookie 请求头或 cookies
+func requireCookie(w cfg.Website) []cfg.Problem {
+	if len(w.Cookies) > 0 {
+		return nil
+	}
+	for key, value := range w.Headers {
+		if strings.EqualFold(key, "Cookie") && value != "" {
+			return nil
+		}
+	}
+	return []cfg.Problem{{Path: "headers.Cookie", Message: "缺少 Cookie"}}
+}
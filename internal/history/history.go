@@ -0,0 +1,161 @@
+package history
+
+import (
+	"auto-checkin/internal/result"
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FileName 数据目录下的历史文件名
+const FileName = "history.jsonl"
+
+// Record 一次签到尝试的历史记录
+type Record struct {
+	Time       time.Time     `json:"time"`
+	Website    string        `json:"website"`
+	Account    string        `json:"account,omitempty"`
+	Status     result.Status `json:"status"`
+	Reward     string        `json:"reward,omitempty"`
+	Balance    string        `json:"balance,omitempty"`
+	Error      string        `json:"error,omitempty"`
+	DurationMs int64         `json:"duration_ms"`
+	DelayMs    int64         `json:"delay_ms,omitempty"`
+}
+
+// FromResult 将签到结果转换为历史记录
+func FromResult(r *result.CheckinResult) Record {
+	rec := Record{
+		Time:       r.StartedAt,
+		Website:    r.Website,
+		Account:    r.Account,
+		Status:     r.Status,
+		Reward:     r.Reward,
+		Balance:    r.Balance,
+		DurationMs: r.Duration().Milliseconds(),
+		DelayMs:    r.Delay.Milliseconds(),
+	}
+	if err := r.Err(); err != nil {
+		rec.Error = err.Error()
+	}
+	return rec
+}
+
+// Filter 历史查询条件，零值字段表示不限制
+type Filter struct {
+	Website string
+	Account string
+	From    time.Time
+	To      time.Time
+}
+
+func (f Filter) match(rec Record) bool {
+	if f.Website != "" && !strings.EqualFold(f.Website, rec.Website) {
+		return false
+	}
+	if f.Account != "" && f.Account != rec.Account {
+		return false
+	}
+	if !f.From.IsZero() && rec.Time.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !rec.Time.Before(f.To) {
+		return false
+	}
+	return true
+}
+
+// Store 基于 JSON Lines 文件的签到历史存储
+type Store struct {
+	path string
+	mu   sync.Mutex
+}
+
+// Open 打开历史存储，文件在首次写入时创建
+func Open(path string) *Store {
+	return &Store{path: path}
+}
+
+// Append 追加历史记录
+func (s *Store) Append(records ...Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	w := bufio.NewWriter(file)
+	enc := json.NewEncoder(w)
+	for _, rec := range records {
+		if err := enc.Encode(rec); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+// Query 按条件查询历史记录，结果按写入顺序返回
+func (s *Store) Query(f Filter) ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.Open(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		var rec Record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return nil, fmt.Errorf("%s 第 %d 行解析失败: %v", s.path, line, err)
+		}
+		if f.match(rec) {
+			records = append(records, rec)
+		}
+	}
+	return records, scanner.Err()
+}
+
+// Streak 计算截至 day 的连续签到天数，day 当天没有成功记录时从前一天开始计算
+func Streak(records []Record, day time.Time, loc *time.Location) int {
+	okDays := make(map[string]bool)
+	for _, rec := range records {
+		if rec.Status.OK() {
+			okDays[rec.Time.In(loc).Format(time.DateOnly)] = true
+		}
+	}
+	day = day.In(loc)
+	if !okDays[day.Format(time.DateOnly)] {
+		day = day.AddDate(0, 0, -1)
+	}
+	streak := 0
+	for okDays[day.Format(time.DateOnly)] {
+		streak++
+		day = day.AddDate(0, 0, -1)
+	}
+	return streak
+}
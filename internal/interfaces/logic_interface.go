@@ -1,8 +1,16 @@
 package interfaces
 
-import "auto-checkin/internal/config"
+import (
+	"auto-checkin/internal/config"
+	"auto-checkin/internal/result"
+	"context"
+)
 
 type Logic interface {
-	Run(website config.Website) string
-	PushContent(format string, args ...any) string
+	Run(ctx context.Context, website config.Website) *result.CheckinResult
+}
+
+// Validator 签到处理器可选实现，用于启动前校验网站配置的必填项
+type Validator interface {
+	Validate(website config.Website) []config.Problem
 }
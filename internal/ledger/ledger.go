@@ -0,0 +1,106 @@
+package ledger
+
+import (
+	"auto-checkin/internal/result"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// FileName 数据目录下的每日签到状态文件名
+const FileName = "state.json"
+
+// keepDays 状态文件保留的天数
+const keepDays = 7
+
+// Entry 某个账号当天的签到状态
+type Entry struct {
+	Status result.Status `json:"status"`
+	Time   time.Time     `json:"time"`
+}
+
+// Ledger 每日签到状态账本，记录各账号当天是否已完成签到
+type Ledger struct {
+	path string
+	mu   sync.Mutex
+	days map[string]map[string]Entry // 日期 -> 账号标识 -> 状态
+}
+
+var (
+	openedMu sync.Mutex
+	opened   = make(map[string]*Ledger)
+)
+
+// Open 加载签到状态账本，文件不存在时返回空账本。
+// 同一进程内相同路径共享一个账本，避免并发执行的签到任务互相覆盖状态
+func Open(path string) (*Ledger, error) {
+	openedMu.Lock()
+	defer openedMu.Unlock()
+	if l, ok := opened[path]; ok {
+		return l, nil
+	}
+
+	l := &Ledger{
+		path: path,
+		days: make(map[string]map[string]Entry),
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			opened[path] = l
+			return l, nil
+		}
+		return l, err
+	}
+	if err := json.Unmarshal(data, &l.days); err != nil {
+		return l, err
+	}
+	opened[path] = l
+	return l, nil
+}
+
+// Done 判断账号在指定日期是否已完成签到
+func (l *Ledger) Done(key, day string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	entry, ok := l.days[day][key]
+	return ok && entry.Status.OK()
+}
+
+// Mark 记录账号在指定日期的签到状态
+func (l *Ledger) Mark(key, day string, status result.Status) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.days[day]; !ok {
+		l.days[day] = make(map[string]Entry)
+	}
+	l.days[day][key] = Entry{Status: status, Time: time.Now()}
+}
+
+// Save 保存账本，并清理过期的日期
+func (l *Ledger) Save() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	cutoff := time.Now().AddDate(0, 0, -keepDays).Format(time.DateOnly)
+	for day := range l.days {
+		if day < cutoff {
+			delete(l.days, day)
+		}
+	}
+	data, err := json.MarshalIndent(l.days, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return err
+	}
+	tmp := l.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, l.path)
+}
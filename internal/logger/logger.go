@@ -44,7 +44,7 @@ func Log() *Logger {
 			maxSize:    10 * 1024 * 1024, // 10MB
 			maxBackups: 5,
 		}
-		if config.Cfg.Debug {
+		if config.Get().Debug {
 			instance.debugLogger = log.New(os.Stdout, "DEBUG: ", log.Ldate|log.Ltime|log.Lshortfile)
 		}
 	})
@@ -76,7 +76,7 @@ func (l *Logger) Init(filename string) error {
 	l.file = file
 
 	// 动态初始化日志输出目标
-	if config.Cfg.Debug {
+	if config.Get().Debug {
 		// Debug 模式下，所有日志等级输出到控制台和文件
 		l.debugLogger = log.New(io.MultiWriter(file, os.Stdout), "DEBUG: ", log.Ldate|log.Ltime)
 		l.infoLogger = log.New(io.MultiWriter(file, os.Stdout), "INFO: ", log.Ldate|log.Ltime)
@@ -100,14 +100,14 @@ func (l *Logger) SetLevel(level int) {
 
 // Debug 记录调试信息
 func (l *Logger) Debug(v ...interface{}) {
-	if config.Cfg.Debug && l.level <= DEBUG && l.debugLogger != nil {
+	if config.Get().Debug && l.level <= DEBUG && l.debugLogger != nil {
 		l.debugLogger.Println(v...)
 	}
 }
 
 // Debugf 格式化记录调试信息
 func (l *Logger) Debugf(format string, v ...interface{}) {
-	if config.Cfg.Debug && l.level <= DEBUG && l.debugLogger != nil {
+	if config.Get().Debug && l.level <= DEBUG && l.debugLogger != nil {
 		l.debugLogger.Printf(format, v...)
 	}
 }
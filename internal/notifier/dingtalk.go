@@ -0,0 +1,119 @@
+package notifier
+
+import (
+	"auto-checkin/internal/config"
+	"auto-checkin/internal/result"
+	"auto-checkin/internal/util"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func init() {
+	RegisterChannel("dingtalk", newDingTalk) // 注册推送渠道
+}
+
+// DingTalkConfig 钉钉自定义机器人配置
+type DingTalkConfig struct {
+	Webhook     string   `json:"webhook"`      // 完整的 webhook 地址，与 access_token 二选一
+	AccessToken string   `json:"access_token"` // webhook 地址中的 access_token
+	Secret      string   `json:"secret"`       // 加签密钥（SEC 开头），配置后使用加签模式
+	Keyword     string   `json:"keyword"`      // 自定义关键词，消息中不包含时自动添加
+	AtMobiles   []string `json:"at_mobiles"`   // 存在签到失败时 @ 的手机号
+	AtUserIDs   []string `json:"at_user_ids"`  // 存在签到失败时 @ 的用户 ID
+	AtAll       bool     `json:"at_all"`       // 存在签到失败时 @ 所有人
+}
+
+// DingTalk 钉钉自定义机器人
+type DingTalk struct {
+	cfg    DingTalkConfig
+	client util.HTTPDoer
+}
+
+func newDingTalk(c config.Channel, client util.HTTPDoer) (Channel, error) {
+	d := &DingTalk{client: client}
+	if err := c.Decode(&d.cfg); err != nil {
+		return nil, err
+	}
+	if d.cfg.Webhook == "" && d.cfg.AccessToken == "" {
+		return nil, errors.New("缺少 webhook 或 access_token")
+	}
+	return d, nil
+}
+
+// dingTalkSign 按钉钉加签规则计算签名：HmacSHA256(timestamp + "\n" + secret)，再进行 Base64 编码
+func dingTalkSign(timestamp int64, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "\n" + secret))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func (d *DingTalk) Send(ctx context.Context, report *result.Report) error {
+	webhook := d.cfg.Webhook
+	if webhook == "" {
+		webhook = "https://oapi.dingtalk.com/robot/send?access_token=" + d.cfg.AccessToken
+	}
+	query := map[string]string{}
+	if d.cfg.Secret != "" {
+		timestamp := time.Now().UnixMilli()
+		query["timestamp"] = strconv.FormatInt(timestamp, 10)
+		query["sign"] = dingTalkSign(timestamp, d.cfg.Secret)
+	}
+
+	text := markdownReport(report)
+	if d.cfg.Keyword != "" && !strings.Contains(text, d.cfg.Keyword) {
+		text = d.cfg.Keyword + "\n\n" + text
+	}
+	at := map[string]any{}
+	if report.Count(result.StatusFailed) > 0 {
+		// @ 手机号需要同时出现在消息正文中才会高亮
+		var mentions []string
+		for _, mobile := range d.cfg.AtMobiles {
+			mentions = append(mentions, "@"+mobile)
+		}
+		for _, id := range d.cfg.AtUserIDs {
+			mentions = append(mentions, "@"+id)
+		}
+		if len(mentions) > 0 {
+			text += "\n\n" + strings.Join(mentions, " ")
+		}
+		if len(d.cfg.AtMobiles) > 0 {
+			at["atMobiles"] = d.cfg.AtMobiles
+		}
+		if len(d.cfg.AtUserIDs) > 0 {
+			at["atUserIds"] = d.cfg.AtUserIDs
+		}
+		at["isAtAll"] = d.cfg.AtAll
+	}
+	payload := map[string]any{
+		"msgtype": "markdown",
+		"markdown": map[string]string{
+			"title": reportTitle,
+			"text":  text,
+		},
+		"at": at,
+	}
+
+	resp, err := util.SendRequest(&util.RequestParams{
+		Context:     ctx,
+		Method:      "POST",
+		URL:         webhook,
+		QueryParams: query,
+		BodyData:    payload,
+		BodyToJson:  true,
+		Client:      d.client,
+	})
+	if err != nil {
+		return err
+	}
+	if errcode, _ := resp["errcode"].(float64); errcode != 0 {
+		return fmt.Errorf("钉钉消息推送失败: %v (errcode %v)", resp["errmsg"], errcode)
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package notifier_test
+
+import (
+	"auto-checkin/internal/config"
+	"auto-checkin/internal/notifier"
+	"auto-checkin/internal/result"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// dingTalkRequest 钉钉替身服务器收到的一次请求
+type dingTalkRequest struct {
+	query   map[string]string
+	payload struct {
+		MsgType  string `json:"msgtype"`
+		Markdown struct {
+			Title string `json:"title"`
+			Text  string `json:"text"`
+		} `json:"markdown"`
+		At map[string]any `json:"at"`
+	}
+}
+
+// newDingTalkServer 启动钉钉机器人替身，记录收到的请求并返回 errcode
+func newDingTalkServer(t *testing.T, errcode int) (*httptest.Server, *[]dingTalkRequest) {
+	t.Helper()
+	var requests []dingTalkRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req dingTalkRequest
+		req.query = map[string]string{}
+		for k := range r.URL.Query() {
+			req.query[k] = r.URL.Query().Get(k)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req.payload); err != nil {
+			t.Errorf("请求体不是 JSON: %v", err)
+		}
+		requests = append(requests, req)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"errcode": errcode, "errmsg": "test"})
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+func newReport(statuses ...result.Status) *result.Report {
+	report := &result.Report{StartedAt: time.Now()}
+	for i, s := range statuses {
+		r := result.New(string(rune('A'+i)), "")
+		r.Status = s
+		report.Results = append(report.Results, r.Finish())
+	}
+	return report
+}
+
+func newChannel(t *testing.T, typ string, settings map[string]any) notifier.Channel {
+	t.Helper()
+	ch, err := notifier.NewChannel(config.Channel{Type: typ, Settings: settings}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ch
+}
+
+func TestDingTalkSign(t *testing.T) {
+	srv, requests := newDingTalkServer(t, 0)
+	const secret = "SECtest"
+	ch := newChannel(t, "dingtalk", map[string]any{"webhook": srv.URL + "/robot/send?access_token=abc", "secret": secret})
+	if err := ch.Send(context.Background(), newReport(result.StatusSigned)); err != nil {
+		t.Fatal(err)
+	}
+	if len(*requests) != 1 {
+		t.Fatalf("收到 %d 个请求，want 1", len(*requests))
+	}
+	q := (*requests)[0].query
+	if q["access_token"] != "abc" {
+		t.Errorf("access_token = %q", q["access_token"])
+	}
+	if q["timestamp"] == "" {
+		t.Fatal("缺少 timestamp")
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(q["timestamp"] + "\n" + secret))
+	if want := base64.StdEncoding.EncodeToString(mac.Sum(nil)); q["sign"] != want {
+		t.Errorf("sign = %q, want %q", q["sign"], want)
+	}
+}
+
+func TestDingTalkNoSecret(t *testing.T) {
+	srv, requests := newDingTalkServer(t, 0)
+	ch := newChannel(t, "dingtalk", map[string]any{"webhook": srv.URL})
+	if err := ch.Send(context.Background(), newReport(result.StatusSigned)); err != nil {
+		t.Fatal(err)
+	}
+	q := (*requests)[0].query
+	if _, ok := q["sign"]; ok {
+		t.Errorf("未配置 secret 时不应附加 sign: %v", q)
+	}
+}
+
+func TestDingTalkKeyword(t *testing.T) {
+	srv, requests := newDingTalkServer(t, 0)
+	ch := newChannel(t, "dingtalk", map[string]any{"webhook": srv.URL, "keyword": "打卡"})
+	if err := ch.Send(context.Background(), newReport(result.StatusSigned)); err != nil {
+		t.Fatal(err)
+	}
+	p := (*requests)[0].payload
+	if p.MsgType != "markdown" {
+		t.Errorf("msgtype = %q", p.MsgType)
+	}
+	if !strings.HasPrefix(p.Markdown.Text, "打卡\n\n") {
+		t.Errorf("正文未以关键词开头: %q", p.Markdown.Text)
+	}
+}
+
+func TestDingTalkMentionsOnlyOnFailure(t *testing.T) {
+	settings := map[string]any{"at_mobiles": []any{"13800000000"}, "at_all": true}
+	cases := []struct {
+		name     string
+		statuses []result.Status
+		mention  bool
+	}{
+		{"all_ok", []result.Status{result.StatusSigned, result.StatusAlreadySigned}, false},
+		{"skipped", []result.Status{result.StatusSkipped}, false},
+		{"failed", []result.Status{result.StatusSigned, result.StatusFailed}, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv, requests := newDingTalkServer(t, 0)
+			settings["webhook"] = srv.URL
+			ch := newChannel(t, "dingtalk", settings)
+			if err := ch.Send(context.Background(), newReport(tc.statuses...)); err != nil {
+				t.Fatal(err)
+			}
+			p := (*requests)[0].payload
+			mobiles, hasMobiles := p.At["atMobiles"].([]any)
+			atAll, _ := p.At["isAtAll"].(bool)
+			if tc.mention {
+				if !hasMobiles || len(mobiles) != 1 || mobiles[0] != "13800000000" {
+					t.Errorf("atMobiles = %v", p.At["atMobiles"])
+				}
+				if !atAll {
+					t.Errorf("isAtAll = %v, want true", p.At["isAtAll"])
+				}
+				if !strings.Contains(p.Markdown.Text, "@13800000000") {
+					t.Errorf("正文缺少 @ 手机号: %q", p.Markdown.Text)
+				}
+				return
+			}
+			if hasMobiles || atAll {
+				t.Errorf("没有失败时不应 @: %v", p.At)
+			}
+			if strings.Contains(p.Markdown.Text, "@13800000000") {
+				t.Errorf("没有失败时正文不应包含 @: %q", p.Markdown.Text)
+			}
+		})
+	}
+}
+
+func TestDingTalkErrcode(t *testing.T) {
+	srv, _ := newDingTalkServer(t, 310000)
+	ch := newChannel(t, "dingtalk", map[string]any{"webhook": srv.URL})
+	if err := ch.Send(context.Background(), newReport(result.StatusSigned)); err == nil {
+		t.Error("errcode 非 0 时应返回错误")
+	}
+}
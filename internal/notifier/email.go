@@ -0,0 +1,281 @@
+package notifier
+
+import (
+	"auto-checkin/internal/config"
+	"auto-checkin/internal/result"
+	"auto-checkin/internal/util"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html/template"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func init() {
+	RegisterChannel("email", newEmail) // 注册推送渠道
+}
+
+// 邮件连接的加密方式
+const (
+	SecurityStartTLS = "starttls" // 明文连接后升级为 TLS（默认端口 587）
+	SecurityTLS      = "tls"      // 隐式 TLS（默认端口 465）
+	SecurityNone     = "none"     // 不加密，仅用于本地中继（默认端口 25）
+)
+
+// EmailConfig SMTP 邮件配置
+type EmailConfig struct {
+	Host               string   `json:"host"`
+	Port               int      `json:"port"`     // 未配置时按加密方式使用 587、465 或 25
+	Security           string   `json:"security"` // starttls（默认）、tls 或 none
+	Username           string   `json:"username"` // 为空时不进行认证
+	Password           string   `json:"password"`
+	From               string   `json:"from"` // 发件人，默认同 username
+	To                 []string `json:"to"`
+	Subject            string   `json:"subject"` // 邮件主题，默认为“签到任务报告”加日期与摘要
+	InsecureSkipVerify bool     `json:"insecure_skip_verify"`
+}
+
+// Email SMTP 邮件，正文同时包含 HTML 表格与纯文本
+type Email struct {
+	cfg EmailConfig
+}
+
+func newEmail(c config.Channel, _ util.HTTPDoer) (Channel, error) {
+	e := &Email{}
+	if err := c.Decode(&e.cfg); err != nil {
+		return nil, err
+	}
+	cfg := &e.cfg
+	if cfg.Security == "" {
+		cfg.Security = SecurityStartTLS
+	}
+	if cfg.Port == 0 {
+		switch cfg.Security {
+		case SecurityTLS:
+			cfg.Port = 465
+		case SecurityNone:
+			cfg.Port = 25
+		default:
+			cfg.Port = 587
+		}
+	}
+	if cfg.From == "" {
+		cfg.From = cfg.Username
+	}
+
+	var problems []string
+	if cfg.Host == "" {
+		problems = append(problems, "缺少 host")
+	}
+	switch cfg.Security {
+	case SecurityStartTLS, SecurityTLS, SecurityNone:
+	default:
+		problems = append(problems, fmt.Sprintf("security 取值无效 %q，可用: starttls、tls、none", cfg.Security))
+	}
+	if _, err := mail.ParseAddress(cfg.From); err != nil {
+		problems = append(problems, fmt.Sprintf("发件人 from 无效: %v", err))
+	}
+	if len(cfg.To) == 0 {
+		problems = append(problems, "缺少收件人 to")
+	}
+	for _, to := range cfg.To {
+		if _, err := mail.ParseAddress(to); err != nil {
+			problems = append(problems, fmt.Sprintf("收件人 %q 无效: %v", to, err))
+		}
+	}
+	if len(problems) > 0 {
+		return nil, errors.New(strings.Join(problems, "；"))
+	}
+	return e, nil
+}
+
+func (e *Email) Send(ctx context.Context, report *result.Report) error {
+	msg, err := e.message(report)
+	if err != nil {
+		return err
+	}
+	c, err := e.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if e.cfg.Username != "" {
+		if ok, _ := c.Extension("AUTH"); !ok {
+			return errors.New("邮件服务器不支持认证")
+		}
+		if err := c.Auth(smtp.PlainAuth("", e.cfg.Username, e.cfg.Password, e.cfg.Host)); err != nil {
+			return fmt.Errorf("邮件服务器认证失败: %v", err)
+		}
+	}
+	from, _ := mail.ParseAddress(e.cfg.From)
+	if err := c.Mail(from.Address); err != nil {
+		return fmt.Errorf("发件人被拒绝: %v", err)
+	}
+	for _, to := range e.cfg.To {
+		addr, _ := mail.ParseAddress(to)
+		if err := c.Rcpt(addr.Address); err != nil {
+			return fmt.Errorf("收件人 %s 被拒绝: %v", addr.Address, err)
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("邮件发送失败: %v", err)
+	}
+	return c.Quit()
+}
+
+// dial 按加密方式连接 SMTP 服务器，连接的读写截止时间跟随 ctx
+func (e *Email) dial(ctx context.Context) (*smtp.Client, error) {
+	addr := net.JoinHostPort(e.cfg.Host, strconv.Itoa(e.cfg.Port))
+	tlsConfig := &tls.Config{ServerName: e.cfg.Host, InsecureSkipVerify: e.cfg.InsecureSkipVerify}
+
+	var conn net.Conn
+	var err error
+	if e.cfg.Security == SecurityTLS {
+		conn, err = (&tls.Dialer{Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("连接邮件服务器失败: %v", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	c, err := smtp.NewClient(conn, e.cfg.Host)
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("连接邮件服务器失败: %v", err)
+	}
+	if e.cfg.Security == SecurityStartTLS {
+		if ok, _ := c.Extension("STARTTLS"); !ok {
+			_ = c.Close()
+			return nil, errors.New("邮件服务器不支持 STARTTLS")
+		}
+		if err := c.StartTLS(tlsConfig); err != nil {
+			_ = c.Close()
+			return nil, fmt.Errorf("STARTTLS 失败: %v", err)
+		}
+	}
+	return c, nil
+}
+
+// subject 返回邮件主题
+func (e *Email) subject(report *result.Report) string {
+	if e.cfg.Subject != "" {
+		return e.cfg.Subject
+	}
+	day := report.StartedAt.In(util.GetTimeLocation()).Format(time.DateOnly)
+	return fmt.Sprintf("%s %s（%s）", reportTitle, day, report.Summary())
+}
+
+// message 构造 multipart/alternative 邮件：纯文本在前作为后备，HTML 在后优先展示
+func (e *Email) message(report *result.Report) ([]byte, error) {
+	htmlBody, err := htmlReport(report)
+	if err != nil {
+		return nil, err
+	}
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	parts := []struct {
+		contentType string
+		content     string
+	}{
+		{"text/plain; charset=UTF-8", report.Text()},
+		{"text/html; charset=UTF-8", htmlBody},
+	}
+	for _, p := range parts {
+		pw, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {p.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qw := quotedprintable.NewWriter(pw)
+		if _, err := qw.Write([]byte(p.content)); err != nil {
+			return nil, err
+		}
+		if err := qw.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	from, _ := mail.ParseAddress(e.cfg.From)
+	to := make([]string, 0, len(e.cfg.To))
+	for _, addr := range e.cfg.To {
+		a, _ := mail.ParseAddress(addr)
+		to = append(to, a.String())
+	}
+	id := make([]byte, 12)
+	_, _ = rand.Read(id)
+
+	var msg bytes.Buffer
+	header := [][2]string{
+		{"From", from.String()},
+		{"To", strings.Join(to, ", ")},
+		{"Subject", mime.BEncoding.Encode("UTF-8", e.subject(report))},
+		{"Date", time.Now().Format(time.RFC1123Z)},
+		{"Message-ID", fmt.Sprintf("<%s@auto-checkin>", hex.EncodeToString(id))},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", "multipart/alternative; boundary=" + mw.Boundary()},
+	}
+	for _, h := range header {
+		msg.WriteString(h[0] + ": " + h[1] + "\r\n")
+	}
+	msg.WriteString("\r\n")
+	msg.Write(body.Bytes())
+	return msg.Bytes(), nil
+}
+
+// htmlTemplate 邮件 HTML 正文，使用内联样式以兼容常见邮件客户端
+var htmlTemplate = template.Must(template.New("email").Parse(`<!DOCTYPE html>
+<html><body style="font-family:-apple-system,'Segoe UI',sans-serif;color:#222">
+<h3>{{.Title}}</h3>
+<p>{{.Report.Summary}}</p>
+<table cellpadding="6" cellspacing="0" border="1" style="border-collapse:collapse;border-color:#ddd">
+<tr style="background:#f5f5f5"><th align="left">网站</th><th align="left">状态</th><th align="left">奖励</th><th align="left">余额</th><th align="left">耗时</th><th align="left">详情</th></tr>
+{{range .Report.Results}}<tr>
+<td>{{.DisplayName}}</td>
+<td style="color:{{if eq .Status.String "failed"}}#c0392b{{else if .Status.OK}}#27ae60{{else}}#7f8c8d{{end}}">{{.Status.Emoji}} {{.Status.Label}}</td>
+<td>{{.Reward}}</td>
+<td>{{.Balance}}</td>
+<td>{{.Duration.Round 1000000}}</td>
+<td>{{range .Lines}}{{.}}<br>{{end}}</td>
+</tr>
+{{end}}</table>
+</body></html>
+`))
+
+// htmlReport 将报告渲染为 HTML 表格
+func htmlReport(report *result.Report) (string, error) {
+	var b strings.Builder
+	err := htmlTemplate.Execute(&b, map[string]any{
+		"Title":  reportTitle,
+		"Report": report,
+	})
+	return b.String(), err
+}
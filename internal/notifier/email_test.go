@@ -0,0 +1,326 @@
+package notifier_test
+
+import (
+	"auto-checkin/internal/config"
+	"auto-checkin/internal/notifier"
+	"auto-checkin/internal/result"
+	"bufio"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"io"
+	"math/big"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// smtpSession 替身服务器记录的一次 SMTP 会话
+type smtpSession struct {
+	tls      bool // 认证与发送时连接是否已加密
+	username string
+	password string
+	from     string
+	rcpts    []string
+	data     string
+}
+
+// fakeSMTP 基于 net.Listener 的最小 SMTP 替身，支持 AUTH PLAIN、STARTTLS 与隐式 TLS
+type fakeSMTP struct {
+	listener net.Listener
+	tls      *tls.Config
+	implicit bool   // 隐式 TLS，连接建立即加密
+	password string // 认证通过的密码
+
+	mu       sync.Mutex
+	sessions []*smtpSession
+	wg       sync.WaitGroup
+}
+
+func newFakeSMTP(t *testing.T, implicit bool) *fakeSMTP {
+	t.Helper()
+	cfg := &tls.Config{Certificates: []tls.Certificate{selfSignedCert(t)}}
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if implicit {
+		l = tls.NewListener(l, cfg)
+	}
+	s := &fakeSMTP{listener: l, tls: cfg, implicit: implicit, password: "pw"}
+	s.wg.Add(1)
+	go s.serve()
+	t.Cleanup(func() {
+		_ = l.Close()
+		s.wg.Wait()
+	})
+	return s
+}
+
+func (s *fakeSMTP) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+// session 返回已完成的会话，等待客户端断开后再读取
+func (s *fakeSMTP) session(t *testing.T) *smtpSession {
+	t.Helper()
+	_ = s.listener.Close()
+	s.wg.Wait()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.sessions) != 1 {
+		t.Fatalf("收到 %d 个会话，want 1", len(s.sessions))
+	}
+	return s.sessions[0]
+}
+
+func (s *fakeSMTP) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.handle(conn)
+		}()
+	}
+}
+
+func (s *fakeSMTP) handle(conn net.Conn) {
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(10 * time.Second))
+	sess := &smtpSession{tls: s.implicit}
+	s.mu.Lock()
+	s.sessions = append(s.sessions, sess)
+	s.mu.Unlock()
+
+	r := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = io.WriteString(conn, line+"\r\n") }
+	reply("220 fake ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		cmd, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(cmd) {
+		case "EHLO", "HELO":
+			ext := "250-fake\r\n250-AUTH PLAIN\r\n"
+			if !sess.tls {
+				ext += "250-STARTTLS\r\n"
+			}
+			_, _ = io.WriteString(conn, ext+"250 8BITMIME\r\n")
+		case "STARTTLS":
+			reply("220 ready")
+			tlsConn := tls.Server(conn, s.tls)
+			if err := tlsConn.Handshake(); err != nil {
+				return
+			}
+			conn, r, sess.tls = tlsConn, bufio.NewReader(tlsConn), true
+		case "AUTH":
+			mech, initial, _ := strings.Cut(arg, " ")
+			raw, err := base64.StdEncoding.DecodeString(initial)
+			parts := strings.Split(string(raw), "\x00")
+			if !strings.EqualFold(mech, "PLAIN") || err != nil || len(parts) != 3 {
+				reply("501 bad auth")
+				continue
+			}
+			sess.username, sess.password = parts[1], parts[2]
+			if parts[2] != s.password {
+				reply("535 authentication failed")
+				continue
+			}
+			reply("235 ok")
+		case "MAIL":
+			sess.from = strings.Trim(strings.SplitN(strings.TrimPrefix(arg, "FROM:"), " ", 2)[0], "<>")
+			reply("250 ok")
+		case "RCPT":
+			sess.rcpts = append(sess.rcpts, strings.Trim(strings.TrimPrefix(arg, "TO:"), "<>"))
+			reply("250 ok")
+		case "DATA":
+			reply("354 go ahead")
+			var b strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				b.WriteString(strings.TrimPrefix(l, "."))
+			}
+			sess.data = b.String()
+			reply("250 queued")
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
+
+// selfSignedCert 生成 127.0.0.1 的自签名证书
+func selfSignedCert(t *testing.T) tls.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+// checkMessage 校验邮件为包含纯文本与 HTML 两部分的 multipart/alternative
+func checkMessage(t *testing.T, data string) {
+	t.Helper()
+	msg, err := mail.ReadMessage(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil || !strings.Contains(subject, "签到任务报告") {
+		t.Errorf("Subject = %q (%v)", subject, err)
+	}
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("Content-Type = %q (%v)", msg.Header.Get("Content-Type"), err)
+	}
+	parts := map[string]string{}
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		p, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := io.ReadAll(p) // quoted-printable 由 multipart.Reader 解码
+		if err != nil {
+			t.Fatal(err)
+		}
+		ct, _, _ := mime.ParseMediaType(p.Header.Get("Content-Type"))
+		parts[ct] = string(body)
+	}
+	if text := parts["text/plain"]; !strings.Contains(text, "[服务]JD签到信息") || !strings.Contains(text, "签到成功") {
+		t.Errorf("纯文本部分不完整: %q", text)
+	}
+	html := parts["text/html"]
+	if !strings.Contains(html, "<table") || !strings.Contains(html, "<td>JD</td>") {
+		t.Errorf("HTML 部分缺少结果表格: %q", html)
+	}
+	if !strings.Contains(html, "&lt;b&gt;") {
+		t.Errorf("HTML 部分未转义网站信息: %q", html)
+	}
+}
+
+func emailReport() *result.Report {
+	ok := result.New("JD", "")
+	ok.Status = result.StatusSigned
+	ok.Push("<b>5京豆</b>")
+	failed := result.New("Quark", "")
+	failed.Push("签名错误")
+	return &result.Report{StartedAt: time.Now(), Results: []*result.CheckinResult{ok.Finish(), failed.Finish()}}
+}
+
+func TestEmailSecurityModes(t *testing.T) {
+	cases := []struct {
+		security string
+		implicit bool
+	}{
+		{"none", false},
+		{"starttls", false},
+		{"tls", true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.security, func(t *testing.T) {
+			srv := newFakeSMTP(t, tc.implicit)
+			ch := newChannel(t, "email", map[string]any{
+				"host":                 "127.0.0.1",
+				"port":                 srv.port(),
+				"security":             tc.security,
+				"insecure_skip_verify": true,
+				"username":             "bot@example.com",
+				"password":             "pw",
+				"to":                   []any{"张三 <a@example.com>", "b@example.com"},
+			})
+			if err := ch.Send(context.Background(), emailReport()); err != nil {
+				t.Fatal(err)
+			}
+			sess := srv.session(t)
+			if wantTLS := tc.security != "none"; sess.tls != wantTLS {
+				t.Errorf("tls = %v, want %v", sess.tls, wantTLS)
+			}
+			if sess.username != "bot@example.com" || sess.password != "pw" {
+				t.Errorf("AUTH = %q/%q", sess.username, sess.password)
+			}
+			if sess.from != "bot@example.com" {
+				t.Errorf("MAIL FROM = %q", sess.from)
+			}
+			if got := strings.Join(sess.rcpts, ","); got != "a@example.com,b@example.com" {
+				t.Errorf("RCPT TO = %q, want one per recipient", got)
+			}
+			checkMessage(t, sess.data)
+		})
+	}
+}
+
+func TestEmailAuthFailure(t *testing.T) {
+	srv := newFakeSMTP(t, false)
+	ch := newChannel(t, "email", map[string]any{
+		"host":     "127.0.0.1",
+		"port":     srv.port(),
+		"security": "none",
+		"username": "bot@example.com",
+		"password": "wrong",
+		"to":       []any{"a@example.com"},
+	})
+	if err := ch.Send(context.Background(), emailReport()); err == nil {
+		t.Fatal("认证失败时应返回错误")
+	}
+	if sess := srv.session(t); sess.data != "" || len(sess.rcpts) > 0 {
+		t.Errorf("认证失败后不应继续发送: %+v", sess)
+	}
+}
+
+func TestEmailConfigErrors(t *testing.T) {
+	cases := map[string]map[string]any{
+		"missing_host": {"username": "bot@example.com", "to": []any{"a@example.com"}},
+		"bad_security": {"host": "smtp.example.com", "security": "ssl", "username": "bot@example.com", "to": []any{"a@example.com"}},
+		"missing_to":   {"host": "smtp.example.com", "username": "bot@example.com"},
+		"bad_to":       {"host": "smtp.example.com", "username": "bot@example.com", "to": []any{"not an address"}},
+	}
+	for name, settings := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := notifier.NewChannel(config.Channel{Type: "email", Settings: settings}, nil); err == nil {
+				t.Error("应返回配置错误")
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package notifier
+
+import (
+	"auto-checkin/internal/config"
+	"auto-checkin/internal/result"
+	"auto-checkin/internal/util"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func init() {
+	RegisterChannel("feishu", newFeishu) // 注册推送渠道
+	RegisterChannel("lark", newFeishu)
+}
+
+// FeishuConfig 飞书/Lark 自定义机器人配置
+type FeishuConfig struct {
+	Webhook string `json:"webhook"` // 完整的 webhook 地址，Lark 使用 open.larksuite.com 域名
+	Secret  string `json:"secret"`  // 签名校验密钥，配置后在请求中附加 timestamp 与 sign
+}
+
+// Feishu 飞书/Lark 自定义机器人，以消息卡片推送报告
+type Feishu struct {
+	cfg    FeishuConfig
+	client util.HTTPDoer
+}
+
+func newFeishu(c config.Channel, client util.HTTPDoer) (Channel, error) {
+	f := &Feishu{client: client}
+	if err := c.Decode(&f.cfg); err != nil {
+		return nil, err
+	}
+	if f.cfg.Webhook == "" {
+		return nil, errors.New("缺少 webhook")
+	}
+	return f, nil
+}
+
+// feishuSign 按飞书签名规则计算签名：以 timestamp + "\n" + secret 为密钥对空字符串做 HmacSHA256，再进行 Base64 编码
+func feishuSign(timestamp int64, secret string) string {
+	mac := hmac.New(sha256.New, []byte(strconv.FormatInt(timestamp, 10)+"\n"+secret))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// headerTemplate 按整体状态返回卡片标题颜色：全部成功为绿色，全部失败为红色，部分失败为橙色，全部跳过为灰色
+func headerTemplate(report *result.Report) string {
+	failed := report.Count(result.StatusFailed)
+	switch {
+	case failed == 0 && report.Count(result.StatusSkipped) == len(report.Results):
+		return "grey"
+	case failed == 0:
+		return "green"
+	case failed == len(report.Results):
+		return "red"
+	default:
+		return "orange"
+	}
+}
+
+// card 将报告渲染为消息卡片，每个网站一个区块
+func (f *Feishu) card(report *result.Report) map[string]any {
+	markdown := func(content string) map[string]any {
+		return map[string]any{"tag": "div", "text": map[string]any{"tag": "lark_md", "content": content}}
+	}
+	elements := []any{markdown(report.Summary())}
+	for _, r := range report.Results {
+		var b strings.Builder
+		b.WriteString("**" + r.Status.Emoji() + " " + r.DisplayName() + "**：" + r.Status.Label())
+		for _, line := range r.Lines() {
+			b.WriteString("\n- " + line)
+		}
+		elements = append(elements, map[string]any{"tag": "hr"}, markdown(b.String()))
+	}
+	return map[string]any{
+		"config": map[string]any{"wide_screen_mode": true},
+		"header": map[string]any{
+			"template": headerTemplate(report),
+			"title":    map[string]any{"tag": "plain_text", "content": reportTitle},
+		},
+		"elements": elements,
+	}
+}
+
+func (f *Feishu) Send(ctx context.Context, report *result.Report) error {
+	payload := map[string]any{
+		"msg_type": "interactive",
+		"card":     f.card(report),
+	}
+	if f.cfg.Secret != "" {
+		timestamp := time.Now().Unix()
+		payload["timestamp"] = strconv.FormatInt(timestamp, 10)
+		payload["sign"] = feishuSign(timestamp, f.cfg.Secret)
+	}
+
+	resp, err := util.SendRequest(&util.RequestParams{
+		Context:    ctx,
+		Method:     "POST",
+		URL:        f.cfg.Webhook,
+		BodyData:   payload,
+		BodyToJson: true,
+		Client:     f.client,
+	})
+	if err != nil {
+		return err
+	}
+	if code, _ := resp["code"].(float64); code != 0 {
+		return fmt.Errorf("飞书消息推送失败: %v (code %v)", resp["msg"], code)
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package notifier_test
+
+import (
+	"auto-checkin/internal/result"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// feishuPayload 飞书替身服务器收到的消息
+type feishuPayload struct {
+	MsgType   string  `json:"msg_type"`
+	Timestamp *string `json:"timestamp"`
+	Sign      *string `json:"sign"`
+	Card      struct {
+		Header struct {
+			Template string `json:"template"`
+			Title    struct {
+				Content string `json:"content"`
+			} `json:"title"`
+		} `json:"header"`
+		Elements []struct {
+			Tag  string `json:"tag"`
+			Text struct {
+				Tag     string `json:"tag"`
+				Content string `json:"content"`
+			} `json:"text"`
+		} `json:"elements"`
+	} `json:"card"`
+}
+
+// newFeishuServer 启动飞书机器人替身，记录收到的消息并返回 code
+func newFeishuServer(t *testing.T, code int) (*httptest.Server, *[]feishuPayload) {
+	t.Helper()
+	var payloads []feishuPayload
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p feishuPayload
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			t.Errorf("请求体不是 JSON: %v", err)
+		}
+		payloads = append(payloads, p)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"code": code, "msg": "test"})
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &payloads
+}
+
+// sendFeishu 发送一次报告并返回替身服务器收到的唯一消息
+func sendFeishu(t *testing.T, settings map[string]any, report *result.Report) feishuPayload {
+	t.Helper()
+	srv, payloads := newFeishuServer(t, 0)
+	settings["webhook"] = srv.URL + "/open-apis/bot/v2/hook/abc"
+	if err := newChannel(t, "feishu", settings).Send(context.Background(), report); err != nil {
+		t.Fatal(err)
+	}
+	if len(*payloads) != 1 {
+		t.Fatalf("收到 %d 个请求，want 1", len(*payloads))
+	}
+	return (*payloads)[0]
+}
+
+func TestFeishuSign(t *testing.T) {
+	const secret = "feishu-secret"
+	before := time.Now().Unix()
+	p := sendFeishu(t, map[string]any{"secret": secret}, newReport(result.StatusSigned))
+	if p.Timestamp == nil || p.Sign == nil {
+		t.Fatalf("缺少 timestamp 或 sign: %+v", p)
+	}
+	ts, err := strconv.ParseInt(*p.Timestamp, 10, 64)
+	if err != nil || ts < before || ts > time.Now().Unix() {
+		t.Errorf("timestamp = %q，应为当前的秒级时间戳", *p.Timestamp)
+	}
+	mac := hmac.New(sha256.New, []byte(*p.Timestamp+"\n"+secret))
+	if want := base64.StdEncoding.EncodeToString(mac.Sum(nil)); *p.Sign != want {
+		t.Errorf("sign = %q, want %q", *p.Sign, want)
+	}
+}
+
+func TestFeishuNoSecret(t *testing.T) {
+	p := sendFeishu(t, map[string]any{}, newReport(result.StatusSigned))
+	if p.Timestamp != nil || p.Sign != nil {
+		t.Errorf("未配置 secret 时不应附加 timestamp 与 sign: %+v", p)
+	}
+}
+
+func TestFeishuHeaderTemplate(t *testing.T) {
+	cases := []struct {
+		name     string
+		statuses []result.Status
+		want     string
+	}{
+		{"all_ok", []result.Status{result.StatusSigned, result.StatusAlreadySigned}, "green"},
+		{"partial", []result.Status{result.StatusSigned, result.StatusFailed}, "orange"},
+		{"failed", []result.Status{result.StatusFailed, result.StatusFailed}, "red"},
+		{"skipped", []result.Status{result.StatusSkipped}, "grey"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := sendFeishu(t, map[string]any{}, newReport(tc.statuses...))
+			if p.MsgType != "interactive" {
+				t.Errorf("msg_type = %q", p.MsgType)
+			}
+			if p.Card.Header.Template != tc.want {
+				t.Errorf("header.template = %q, want %q", p.Card.Header.Template, tc.want)
+			}
+			if p.Card.Header.Title.Content == "" {
+				t.Error("卡片缺少标题")
+			}
+		})
+	}
+}
+
+func TestFeishuSectionPerWebsite(t *testing.T) {
+	report := newReport(result.StatusSigned, result.StatusFailed, result.StatusSkipped)
+	report.Results[1].Push("签名错误")
+	p := sendFeishu(t, map[string]any{}, report)
+
+	// 摘要之后每个网站一个分隔线与一个区块
+	elements := p.Card.Elements
+	if len(elements) != 1+2*len(report.Results) {
+		t.Fatalf("elements = %d, want %d", len(elements), 1+2*len(report.Results))
+	}
+	if elements[0].Tag != "div" || elements[0].Text.Content != report.Summary() {
+		t.Errorf("第一个区块应为摘要: %+v", elements[0])
+	}
+	for i, r := range report.Results {
+		hr, div := elements[1+2*i], elements[2+2*i]
+		if hr.Tag != "hr" {
+			t.Errorf("elements[%d].tag = %q, want hr", 1+2*i, hr.Tag)
+		}
+		if div.Tag != "div" || div.Text.Tag != "lark_md" {
+			t.Errorf("elements[%d] = %+v, want lark_md div", 2+2*i, div)
+		}
+		if !strings.Contains(div.Text.Content, r.DisplayName()) || !strings.Contains(div.Text.Content, r.Status.Label()) {
+			t.Errorf("%s 的区块缺少名称或状态: %q", r.DisplayName(), div.Text.Content)
+		}
+	}
+	if !strings.Contains(elements[4].Text.Content, "签名错误") {
+		t.Errorf("失败网站的区块缺少失败原因: %q", elements[4].Text.Content)
+	}
+}
+
+func TestFeishuErrorCode(t *testing.T) {
+	srv, _ := newFeishuServer(t, 19021)
+	ch := newChannel(t, "feishu", map[string]any{"webhook": srv.URL})
+	if err := ch.Send(context.Background(), newReport(result.StatusSigned)); err == nil {
+		t.Error("code 非 0 时应返回错误")
+	}
+}
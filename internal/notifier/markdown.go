@@ -0,0 +1,25 @@
+package notifier
+
+import (
+	"auto-checkin/internal/result"
+	"strings"
+)
+
+// reportTitle 推送消息的标题
+const reportTitle = "签到任务报告"
+
+// markdownReport 将报告渲染为 Markdown，适用于钉钉等支持 Markdown 消息的渠道
+func markdownReport(report *result.Report) string {
+	var b strings.Builder
+	b.WriteString("### " + reportTitle + "\n\n")
+	b.WriteString(report.Summary() + "\n\n")
+	for _, r := range report.Results {
+		b.WriteString("---\n\n")
+		b.WriteString("**" + r.Status.Emoji() + " " + r.DisplayName() + "**：" + r.Status.Label() + "\n\n")
+		for _, line := range r.Lines() {
+			b.WriteString("- " + line + "\n")
+		}
+		b.WriteString("\n")
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
@@ -3,111 +3,102 @@ package notifier
 import (
 	"auto-checkin/internal/config"
 	"auto-checkin/internal/logger"
+	"auto-checkin/internal/result"
 	"auto-checkin/internal/util"
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
-	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 )
 
-type Notifier struct {
+// pushTimeout 单次推送（所有渠道并行）的超时时间
+const pushTimeout = 60 * time.Second
+
+// Channel 推送渠道
+type Channel interface {
+	Send(ctx context.Context, report *result.Report) error
 }
 
-func New() *Notifier {
-	return &Notifier{}
+// Factory 推送渠道工厂，根据渠道配置创建实例，配置无效时返回错误
+type Factory func(c config.Channel, client util.HTTPDoer) (Channel, error)
+
+// Channels 全局工厂，存储所有推送渠道
+var Channels = make(map[string]Factory)
+
+// RegisterChannel 注册推送渠道
+func RegisterChannel(name string, factory Factory) {
+	Channels[strings.ToLower(name)] = factory
 }
 
-func (n *Notifier) Push(message string) {
-	err := n.SendTelegram(message)
-	if err != nil {
-		logger.Log().Errorf("telegram消息推送失败: %v", err)
-		return
-	}
-	err = n.SendWeCom(message)
-	if err != nil {
-		logger.Log().Errorf("企微消息推送失败: %v", err)
-		return
+// ChannelTypes 返回已注册的推送渠道类型（已排序）
+func ChannelTypes() []string {
+	types := make([]string, 0, len(Channels))
+	for t := range Channels {
+		types = append(types, t)
 	}
+	sort.Strings(types)
+	return types
 }
 
-func (n *Notifier) SendWeCom(message string) error {
-	if config.Cfg.Notifications.WeCom.KEY == "" {
-		logger.Log().Debug("未配置企微消息推送")
-		return nil
-	}
-	logger.Log().Debug("开始执行企微消息推送")
-	payload := map[string]interface{}{
-		"msgtype": "text",
-		"text": map[string]string{
-			"content": message,
-		},
-	}
-	webhook := fmt.Sprintf("https://qyapi.weixin.qq.com/cgi-bin/webhook/send?key=%s", config.Cfg.Notifications.WeCom.KEY)
-	jsonPayload, err := json.Marshal(payload)
-	if err != nil {
-		return err
-	}
-	resp, err := util.SendRequest(&util.RequestParams{
-		Method:             "POST",
-		URL:                webhook,
-		QueryParams:        nil,
-		BodyData:           jsonPayload,
-		InsecureSkipVerify: true,
-	})
-	if err != nil {
-		return err
-	}
-	errcode, ok := resp["errcode"].(float64)
+// NewChannel 根据配置创建推送渠道
+func NewChannel(c config.Channel, client util.HTTPDoer) (Channel, error) {
+	factory, ok := Channels[strings.ToLower(c.Type)]
 	if !ok {
-		logger.Log().Error("企微消息推送失败: 无效的errcode类型")
-		return fmt.Errorf("企微消息推送失败: 无效的errcode类型")
+		return nil, fmt.Errorf("不支持的推送渠道 %q，可用: %s", c.Type, strings.Join(ChannelTypes(), ", "))
 	}
-	if errcode != 0 {
-		errmsg, ok := resp["errmsg"].(string)
-		if !ok {
-			logger.Log().Error("企微消息推送失败: 无效的errmsg类型")
-			return fmt.Errorf("企微消息推送失败: 无效的errmsg类型")
-		}
-		logger.Log().Errorf("企微消息推送失败: %s", errmsg)
-		return fmt.Errorf("企微消息推送失败: %s", errmsg)
-	}
-	logger.Log().Info("telegram推送成功！")
-	return nil
+	return factory(c, client)
+}
+
+type Notifier struct {
+	client util.HTTPDoer // 推送使用的客户端，为空时按请求参数新建
 }
 
-func (n *Notifier) SendTelegram(message string) error {
-	if config.Cfg.Notifications.Telegram.BotToken == "" || config.Cfg.Notifications.Telegram.UID == "" {
-		logger.Log().Debug("tg 服务的 bot_token 或者 user_id 未设置!!")
+// New 创建推送模块，client 为空时使用默认客户端
+func New(client util.HTTPDoer) *Notifier {
+	return &Notifier{client: client}
+}
+
+// Push 将报告并行推送到当前配置的所有渠道，各渠道互不影响，返回所有失败渠道的错误
+func (n *Notifier) Push(ctx context.Context, report *result.Report) error {
+	channels := config.Get().Notifications
+	if len(channels) == 0 {
+		logger.Log().Debug("未配置推送渠道")
 		return nil
 	}
-	logger.Log().Info("开始执行Telegram消息推送")
-	var apiUrl string
-	if config.Cfg.Notifications.Telegram.APIHost != "" {
-		apiUrl = fmt.Sprintf("https://%s/bot%s/sendMessage", config.Cfg.Notifications.Telegram.APIHost, config.Cfg.Notifications.Telegram.BotToken)
-	} else {
-		apiUrl = fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", config.Cfg.Notifications.Telegram.BotToken)
-	}
-	logger.Log().Debug("开始拼装telegram消息推送参数")
-	formData := url.Values{}
-	formData.Add("chat_id", config.Cfg.Notifications.Telegram.UID)
-	formData.Add("text", message)
-	formData.Add("disable_web_page_preview", "true")
+	// 签到任务超时后仍需推送报告，推送使用独立的超时时间
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), pushTimeout)
+	defer cancel()
 
-	logger.Log().Info("开始执行Telegram消息推送")
-	result, err := util.SendRequest(&util.RequestParams{
-		Method:             "POST",
-		URL:                apiUrl,
-		BodyData:           formData,
-		InsecureSkipVerify: false,
-		Proxy:              true,
-	})
-	if err != nil {
-		logger.Log().Errorf("telegram消息推送失败: %v", err)
-		return fmt.Errorf("telegram消息推送失败: %v", err)
+	errs := make([]error, len(channels))
+	var wg sync.WaitGroup
+	for i, c := range channels {
+		wg.Add(1)
+		go func(i int, c config.Channel) {
+			defer wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					errs[i] = fmt.Errorf("%s: 推送异常: %v", c.Label(), r)
+				}
+			}()
+			ch, err := NewChannel(c, n.client)
+			if err == nil {
+				err = ch.Send(ctx, report)
+			}
+			if err != nil {
+				errs[i] = fmt.Errorf("%s: %w", c.Label(), err)
+				return
+			}
+			logger.Log().Infof("%s 推送成功", c.Label())
+		}(i, c)
 	}
-	if ok, exists := result["ok"].(bool); exists && ok {
-		logger.Log().Info("telegram推送成功！")
-	} else {
-		logger.Log().Error("telegram推送失败！")
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			logger.Log().Errorf("消息推送失败: %v", err)
+		}
 	}
-	return nil
+	return errors.Join(errs...)
 }
@@ -0,0 +1,77 @@
+package notifier
+
+import (
+	"auto-checkin/internal/config"
+	"auto-checkin/internal/logger"
+	"auto-checkin/internal/result"
+	"auto-checkin/internal/util"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+func init() {
+	RegisterChannel("telegram", newTelegram) // 注册推送渠道
+}
+
+// TelegramConfig Telegram 机器人配置
+type TelegramConfig struct {
+	BotToken string `json:"bot_token"`
+	UID      string `json:"uid"`
+	APIHost  string `json:"api_host"`
+	ChatID   string `json:"chat_id"` // 未配置 uid 时使用
+}
+
+// Telegram Telegram 机器人
+type Telegram struct {
+	cfg    TelegramConfig
+	client util.HTTPDoer
+}
+
+func newTelegram(c config.Channel, client util.HTTPDoer) (Channel, error) {
+	t := &Telegram{client: client}
+	if err := c.Decode(&t.cfg); err != nil {
+		return nil, err
+	}
+	if t.cfg.BotToken == "" || (t.cfg.UID == "" && t.cfg.ChatID == "") {
+		return nil, errors.New("缺少 bot_token 或 uid")
+	}
+	return t, nil
+}
+
+func (t *Telegram) Send(ctx context.Context, report *result.Report) error {
+	var apiUrl string
+	if t.cfg.APIHost != "" {
+		apiUrl = fmt.Sprintf("https://%s/bot%s/sendMessage", t.cfg.APIHost, t.cfg.BotToken)
+	} else {
+		apiUrl = fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", t.cfg.BotToken)
+	}
+	logger.Log().Debug("开始拼装telegram消息推送参数")
+	chatID := t.cfg.UID
+	if chatID == "" {
+		chatID = t.cfg.ChatID
+	}
+	formData := url.Values{}
+	formData.Add("chat_id", chatID)
+	formData.Add("text", report.Text())
+	formData.Add("disable_web_page_preview", "true")
+
+	logger.Log().Info("开始执行Telegram消息推送")
+	result, err := util.SendRequest(&util.RequestParams{
+		Context:            ctx,
+		Method:             "POST",
+		URL:                apiUrl,
+		BodyData:           formData,
+		InsecureSkipVerify: false,
+		Proxy:              true,
+		Client:             t.client,
+	})
+	if err != nil {
+		return fmt.Errorf("telegram消息推送失败: %v", err)
+	}
+	if ok, exists := result["ok"].(bool); !exists || !ok {
+		return fmt.Errorf("telegram消息推送失败: %v", result["description"])
+	}
+	return nil
+}
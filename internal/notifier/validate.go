@@ -0,0 +1,22 @@
+package notifier
+
+import (
+	"auto-checkin/internal/config"
+	"fmt"
+)
+
+func init() {
+	config.RegisterValidator(validateChannels)
+}
+
+// validateChannels 校验推送渠道类型与各渠道的配置
+func validateChannels(c *config.Config) []config.Problem {
+	var problems []config.Problem
+	for i, ch := range c.Notifications {
+		path := fmt.Sprintf("notifications[%d](%s)", i, ch.Label())
+		if _, err := NewChannel(ch, nil); err != nil {
+			problems = append(problems, config.Problem{Path: path, Message: err.Error()})
+		}
+	}
+	return problems
+}
@@ -0,0 +1,217 @@
+package notifier
+
+import (
+	"auto-checkin/internal/config"
+	"auto-checkin/internal/result"
+	"auto-checkin/internal/util"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"text/template"
+	"time"
+)
+
+func init() {
+	RegisterChannel("webhook", newWebhook) // 注册推送渠道
+}
+
+// defaultSignatureHeader 配置了 secret 但未指定签名请求头时使用的请求头
+const defaultSignatureHeader = "X-Signature"
+
+// WebhookConfig 通用 webhook 配置，请求体由模板渲染，可对接 Gotify、ntfy、Bark、Server 酱、PushPlus 等服务
+type WebhookConfig struct {
+	URL                string            `json:"url"`              // 请求地址，可使用模板
+	Method             string            `json:"method"`           // 请求方法，默认 POST；GET、HEAD 不发送请求体
+	Headers            map[string]string `json:"headers"`          // 自定义请求头，未指定 Content-Type 时使用 application/json
+	Template           string            `json:"template"`         // 请求体模板（text/template），为空时发送完整的 JSON 报告
+	Secret             string            `json:"secret"`           // 签名密钥，配置后对请求体做 HmacSHA256 并以十六进制写入签名请求头
+	SignatureHeader    string            `json:"signature_header"` // 签名请求头，默认 X-Signature
+	Proxy              bool              `json:"proxy"`            // 是否经过 proxy 配置的代理
+	InsecureSkipVerify bool              `json:"insecure_skip_verify"`
+}
+
+// Webhook 通用 webhook，将报告按模板渲染后发送到任意地址
+type Webhook struct {
+	cfg      WebhookConfig
+	url      *template.Template
+	template *template.Template
+	client   util.HTTPDoer
+}
+
+// webhookFuncs 模板中可用的辅助函数
+var webhookFuncs = template.FuncMap{
+	// json 将值编码为 JSON，字符串会带上引号并转义，可直接嵌入 JSON 模板
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	"join": strings.Join,
+}
+
+// WebhookPayload 模板数据，模板为空时以 JSON 形式作为请求体发送
+type WebhookPayload struct {
+	Title      string          `json:"title"`
+	Summary    string          `json:"summary"`  // 各状态数量的一行摘要
+	Text       string          `json:"text"`     // 纯文本报告
+	Markdown   string          `json:"markdown"` // Markdown 报告
+	Success    int             `json:"success"`
+	Failed     int             `json:"failed"`
+	Skipped    int             `json:"skipped"`
+	StartedAt  time.Time       `json:"started_at"`
+	FinishedAt time.Time       `json:"finished_at"`
+	Results    []WebhookResult `json:"results"`
+}
+
+// WebhookResult 单个网站的签到结果
+type WebhookResult struct {
+	Website    string   `json:"website"`
+	Account    string   `json:"account,omitempty"`
+	Name       string   `json:"name"`   // 展示名称，多账号时附带账号
+	Status     string   `json:"status"` // signed、already-signed、failed、skipped 等
+	Label      string   `json:"label"`  // 状态的中文说明
+	Emoji      string   `json:"emoji"`
+	OK         bool     `json:"ok"` // 是否视为签到完成
+	Reward     string   `json:"reward,omitempty"`
+	Balance    string   `json:"balance,omitempty"`
+	DurationMs int64    `json:"duration_ms"`
+	Lines      []string `json:"lines"` // 展示信息与失败原因
+}
+
+// newWebhookPayload 将报告转换为模板数据
+func newWebhookPayload(report *result.Report) WebhookPayload {
+	p := WebhookPayload{
+		Title:      reportTitle,
+		Summary:    report.Summary(),
+		Text:       report.Text(),
+		Markdown:   markdownReport(report),
+		Success:    report.Count(result.StatusSigned) + report.Count(result.StatusAlreadySigned),
+		Failed:     report.Count(result.StatusFailed),
+		Skipped:    report.Count(result.StatusSkipped),
+		StartedAt:  report.StartedAt,
+		FinishedAt: report.FinishedAt,
+		Results:    make([]WebhookResult, 0, len(report.Results)),
+	}
+	for _, r := range report.Results {
+		lines := r.Lines()
+		if lines == nil {
+			lines = []string{}
+		}
+		p.Results = append(p.Results, WebhookResult{
+			Website:    r.Website,
+			Account:    r.Account,
+			Name:       r.DisplayName(),
+			Status:     r.Status.String(),
+			Label:      r.Status.Label(),
+			Emoji:      r.Status.Emoji(),
+			OK:         r.Status.OK(),
+			Reward:     r.Reward,
+			Balance:    r.Balance,
+			DurationMs: r.Duration().Milliseconds(),
+			Lines:      lines,
+		})
+	}
+	return p
+}
+
+func newWebhook(c config.Channel, client util.HTTPDoer) (Channel, error) {
+	w := &Webhook{client: client}
+	if err := c.Decode(&w.cfg); err != nil {
+		return nil, err
+	}
+	cfg := &w.cfg
+	cfg.Method = strings.ToUpper(cfg.Method)
+	if cfg.Method == "" {
+		cfg.Method = http.MethodPost
+	}
+	if cfg.Template == "" {
+		cfg.Template = "{{json .}}"
+	}
+	if cfg.Secret != "" && cfg.SignatureHeader == "" {
+		cfg.SignatureHeader = defaultSignatureHeader
+	}
+
+	var problems []string
+	if cfg.URL == "" {
+		problems = append(problems, "缺少 url")
+	} else if !strings.Contains(cfg.URL, "{{") {
+		if u, err := url.Parse(cfg.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			problems = append(problems, fmt.Sprintf("url 无效 %q，需以 http:// 或 https:// 开头", cfg.URL))
+		}
+	}
+	var err error
+	if w.url, err = template.New("url").Funcs(webhookFuncs).Parse(cfg.URL); err != nil {
+		problems = append(problems, fmt.Sprintf("url 模板无效: %v", err))
+	}
+	if w.template, err = template.New("template").Funcs(webhookFuncs).Parse(cfg.Template); err != nil {
+		problems = append(problems, fmt.Sprintf("template 模板无效: %v", err))
+	}
+	if len(problems) > 0 {
+		return nil, errors.New(strings.Join(problems, "；"))
+	}
+	return w, nil
+}
+
+// webhookSign 计算请求体的 HmacSHA256 签名（十六进制）
+func webhookSign(body, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// render 使用模板渲染报告
+func render(tpl *template.Template, payload WebhookPayload) (string, error) {
+	var b strings.Builder
+	if err := tpl.Execute(&b, payload); err != nil {
+		return "", fmt.Errorf("%s 渲染失败: %v", tpl.Name(), err)
+	}
+	return b.String(), nil
+}
+
+func (w *Webhook) Send(ctx context.Context, report *result.Report) error {
+	payload := newWebhookPayload(report)
+	target, err := render(w.url, payload)
+	if err != nil {
+		return err
+	}
+
+	headers := make(map[string]string, len(w.cfg.Headers)+2)
+	contentType := false
+	for k, v := range w.cfg.Headers {
+		headers[k] = v
+		contentType = contentType || strings.EqualFold(k, "Content-Type")
+	}
+	var body any
+	if w.cfg.Method != http.MethodGet && w.cfg.Method != http.MethodHead {
+		text, err := render(w.template, payload)
+		if err != nil {
+			return err
+		}
+		body = text
+		if !contentType {
+			headers["Content-Type"] = "application/json"
+		}
+		if w.cfg.Secret != "" {
+			headers[w.cfg.SignatureHeader] = webhookSign(text, w.cfg.Secret)
+		}
+	}
+
+	_, err = util.SendRequest(&util.RequestParams{
+		Context:            ctx,
+		Method:             w.cfg.Method,
+		URL:                target,
+		BodyData:           body,
+		Headers:            headers,
+		Proxy:              w.cfg.Proxy,
+		InsecureSkipVerify: w.cfg.InsecureSkipVerify,
+		Client:             w.client,
+		AllowNonJSON:       true,
+	})
+	return err
+}
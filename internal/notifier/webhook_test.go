@@ -0,0 +1,171 @@
+package notifier_test
+
+import (
+	"auto-checkin/internal/result"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// webhookRequest webhook 替身服务器收到的一次请求
+type webhookRequest struct {
+	method string
+	path   string
+	query  string
+	header http.Header
+	body   []byte // 原始请求体
+}
+
+// newWebhookServer 启动 webhook 替身，按原样记录收到的请求
+func newWebhookServer(t *testing.T, status int) (*httptest.Server, *[]webhookRequest) {
+	t.Helper()
+	var requests []webhookRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("读取请求体失败: %v", err)
+		}
+		requests = append(requests, webhookRequest{
+			method: r.Method,
+			path:   r.URL.Path,
+			query:  r.URL.RawQuery,
+			header: r.Header.Clone(),
+			body:   body,
+		})
+		w.WriteHeader(status)
+		_, _ = io.WriteString(w, "ok")
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+// sendWebhook 向替身服务器的 path 发送一次报告，返回收到的唯一请求
+func sendWebhook(t *testing.T, path string, settings map[string]any, report *result.Report) webhookRequest {
+	t.Helper()
+	srv, requests := newWebhookServer(t, http.StatusOK)
+	settings["url"] = srv.URL + path
+	if err := newChannel(t, "webhook", settings).Send(context.Background(), report); err != nil {
+		t.Fatal(err)
+	}
+	if len(*requests) != 1 {
+		t.Fatalf("收到 %d 个请求，want 1", len(*requests))
+	}
+	return (*requests)[0]
+}
+
+func TestWebhookTemplate(t *testing.T) {
+	report := newReport(result.StatusSigned, result.StatusFailed)
+	req := sendWebhook(t, "/hook", map[string]any{
+		"template": `{"title":{{json .Title}},"failed":{{.Failed}},"names":[{{range $i, $r := .Results}}{{if $i}},{{end}}{{json $r.Name}}{{end}}]}`,
+	}, report)
+	if req.method != http.MethodPost || req.path != "/hook" {
+		t.Errorf("请求 = %s %s", req.method, req.path)
+	}
+	want := `{"title":"签到任务报告","failed":1,"names":["A","B"]}`
+	if string(req.body) != want {
+		t.Errorf("body = %s\nwant %s", req.body, want)
+	}
+	if ct := req.header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+}
+
+func TestWebhookDefaultPayload(t *testing.T) {
+	req := sendWebhook(t, "/hook", map[string]any{}, newReport(result.StatusSigned, result.StatusSkipped))
+	var p struct {
+		Success int `json:"success"`
+		Skipped int `json:"skipped"`
+		Results []struct {
+			Name   string `json:"name"`
+			Status string `json:"status"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(req.body, &p); err != nil {
+		t.Fatalf("默认请求体不是 JSON 报告: %v\n%s", err, req.body)
+	}
+	if p.Success != 1 || p.Skipped != 1 || len(p.Results) != 2 || p.Results[0].Status != "signed" {
+		t.Errorf("默认请求体 = %s", req.body)
+	}
+}
+
+func TestWebhookHeaders(t *testing.T) {
+	req := sendWebhook(t, "/message?token={{urlquery .Title}}", map[string]any{
+		"template": "{{.Summary}}",
+		"headers": map[string]any{
+			"Content-Type":  "text/plain; charset=utf-8",
+			"Authorization": "Bearer abc",
+			"X-Priority":    "5",
+		},
+	}, newReport(result.StatusSigned))
+	if got := req.header.Get("Content-Type"); got != "text/plain; charset=utf-8" {
+		t.Errorf("Content-Type = %q，自定义请求头应覆盖默认值", got)
+	}
+	if got := req.header.Get("Authorization"); got != "Bearer abc" {
+		t.Errorf("Authorization = %q", got)
+	}
+	if got := req.header.Get("X-Priority"); got != "5" {
+		t.Errorf("X-Priority = %q", got)
+	}
+	if req.path != "/message" || req.query == "" {
+		t.Errorf("url 模板未渲染: %s?%s", req.path, req.query)
+	}
+	if req.header.Get("X-Signature") != "" {
+		t.Error("未配置 secret 时不应附加签名")
+	}
+}
+
+func TestWebhookSignature(t *testing.T) {
+	const secret = "webhook-secret"
+	cases := []struct {
+		name     string
+		settings map[string]any
+		header   string
+	}{
+		{"default_header", map[string]any{"secret": secret}, "X-Signature"},
+		{"custom_header", map[string]any{
+			"secret":           secret,
+			"signature_header": "X-Hub-Signature-256",
+			"template":         "{{.Text}}",
+		}, "X-Hub-Signature-256"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := sendWebhook(t, "/hook", tc.settings, newReport(result.StatusSigned, result.StatusFailed))
+			if len(req.body) == 0 {
+				t.Fatal("请求体为空")
+			}
+			mac := hmac.New(sha256.New, []byte(secret))
+			mac.Write(req.body)
+			if got, want := req.header.Get(tc.header), hex.EncodeToString(mac.Sum(nil)); got != want {
+				t.Errorf("%s = %q, want HMAC of sent body %q", tc.header, got, want)
+			}
+		})
+	}
+}
+
+func TestWebhookGetHasNoBody(t *testing.T) {
+	req := sendWebhook(t, "/push?text={{urlquery .Summary}}", map[string]any{
+		"method": "get",
+		"secret": "s",
+	}, newReport(result.StatusSigned))
+	if req.method != http.MethodGet || len(req.body) != 0 {
+		t.Errorf("GET 请求不应发送请求体: %s %q", req.method, req.body)
+	}
+	if req.header.Get("X-Signature") != "" {
+		t.Error("没有请求体时不应附加签名")
+	}
+}
+
+func TestWebhookErrorStatus(t *testing.T) {
+	srv, _ := newWebhookServer(t, http.StatusUnauthorized)
+	ch := newChannel(t, "webhook", map[string]any{"url": srv.URL})
+	if err := ch.Send(context.Background(), newReport(result.StatusSigned)); err == nil {
+		t.Error("状态码 401 时应返回错误")
+	}
+}
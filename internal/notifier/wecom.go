@@ -0,0 +1,77 @@
+package notifier
+
+import (
+	"auto-checkin/internal/config"
+	"auto-checkin/internal/logger"
+	"auto-checkin/internal/result"
+	"auto-checkin/internal/util"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+func init() {
+	RegisterChannel("wecom", newWeCom) // 注册推送渠道
+}
+
+// WeComConfig 企业微信群机器人配置
+type WeComConfig struct {
+	KEY string `json:"key"`
+}
+
+// WeCom 企业微信群机器人
+type WeCom struct {
+	cfg    WeComConfig
+	client util.HTTPDoer
+}
+
+func newWeCom(c config.Channel, client util.HTTPDoer) (Channel, error) {
+	w := &WeCom{client: client}
+	if err := c.Decode(&w.cfg); err != nil {
+		return nil, err
+	}
+	if w.cfg.KEY == "" {
+		return nil, errors.New("缺少 key")
+	}
+	return w, nil
+}
+
+func (w *WeCom) Send(ctx context.Context, report *result.Report) error {
+	logger.Log().Debug("开始执行企微消息推送")
+	payload := map[string]interface{}{
+		"msgtype": "text",
+		"text": map[string]string{
+			"content": report.Text(),
+		},
+	}
+	webhook := fmt.Sprintf("https://qyapi.weixin.qq.com/cgi-bin/webhook/send?key=%s", w.cfg.KEY)
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	resp, err := util.SendRequest(&util.RequestParams{
+		Context:            ctx,
+		Method:             "POST",
+		URL:                webhook,
+		QueryParams:        nil,
+		BodyData:           jsonPayload,
+		InsecureSkipVerify: true,
+		Client:             w.client,
+	})
+	if err != nil {
+		return err
+	}
+	errcode, ok := resp["errcode"].(float64)
+	if !ok {
+		return fmt.Errorf("企微消息推送失败: 无效的errcode类型")
+	}
+	if errcode != 0 {
+		errmsg, ok := resp["errmsg"].(string)
+		if !ok {
+			return fmt.Errorf("企微消息推送失败: 无效的errmsg类型")
+		}
+		return fmt.Errorf("企微消息推送失败: %s", errmsg)
+	}
+	return nil
+}
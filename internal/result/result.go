@@ -0,0 +1,229 @@
+package result
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Status 签到结果状态
+type Status int
+
+const (
+	// StatusUnknown 处理器尚未给出结论
+	StatusUnknown Status = iota
+	// StatusSigned 本次签到成功
+	StatusSigned
+	// StatusAlreadySigned 今日已签到
+	StatusAlreadySigned
+	// StatusFailed 签到失败
+	StatusFailed
+	// StatusSkipped 未执行签到
+	StatusSkipped
+)
+
+var statusNames = map[Status]string{
+	StatusUnknown:       "unknown",
+	StatusSigned:        "signed",
+	StatusAlreadySigned: "already-signed",
+	StatusFailed:        "failed",
+	StatusSkipped:       "skipped",
+}
+
+func (s Status) String() string {
+	if name, ok := statusNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("status(%d)", int(s))
+}
+
+// MarshalText 以文本形式序列化状态
+func (s Status) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText 从文本解析状态
+func (s *Status) UnmarshalText(text []byte) error {
+	st, err := ParseStatus(string(text))
+	if err != nil {
+		return err
+	}
+	*s = st
+	return nil
+}
+
+// ParseStatus 根据名称解析状态
+func ParseStatus(name string) (Status, error) {
+	for st, n := range statusNames {
+		if n == name {
+			return st, nil
+		}
+	}
+	return StatusUnknown, fmt.Errorf("未知的签到状态: %s", name)
+}
+
+// Emoji 状态对应的展示符号
+func (s Status) Emoji() string {
+	switch s {
+	case StatusSigned, StatusAlreadySigned:
+		return "✅"
+	case StatusFailed:
+		return "❌"
+	case StatusSkipped:
+		return "⏭️"
+	default:
+		return "❔"
+	}
+}
+
+// Label 状态对应的中文描述
+func (s Status) Label() string {
+	switch s {
+	case StatusSigned:
+		return "签到成功"
+	case StatusAlreadySigned:
+		return "今日已签到"
+	case StatusFailed:
+		return "签到失败"
+	case StatusSkipped:
+		return "已跳过"
+	default:
+		return "状态未知"
+	}
+}
+
+// OK 是否视为签到完成
+func (s Status) OK() bool {
+	return s == StatusSigned || s == StatusAlreadySigned
+}
+
+// CheckinResult 单个网站的签到结果
+type CheckinResult struct {
+	Website    string
+	Account    string
+	Status     Status
+	Reward     string
+	Balance    string
+	Messages   []string
+	Errors     []error
+	Delay      time.Duration // 签到前的随机延迟
+	StartedAt  time.Time
+	FinishedAt time.Time
+}
+
+// New 创建签到结果并记录开始时间
+func New(website, account string) *CheckinResult {
+	return &CheckinResult{
+		Website:   website,
+		Account:   account,
+		StartedAt: time.Now(),
+	}
+}
+
+// DisplayName 展示名称，多账号时附带账号
+func (r *CheckinResult) DisplayName() string {
+	if r.Account == "" {
+		return r.Website
+	}
+	return r.Website + "(" + r.Account + ")"
+}
+
+// Push 追加一条展示信息
+func (r *CheckinResult) Push(format string, args ...any) {
+	r.Messages = append(r.Messages, fmt.Sprintf(format, args...))
+}
+
+// Fail 记录错误并将状态置为失败
+func (r *CheckinResult) Fail(err error) {
+	if err != nil {
+		r.Errors = append(r.Errors, err)
+	}
+	r.Status = StatusFailed
+}
+
+// Err 返回合并后的错误链
+func (r *CheckinResult) Err() error {
+	return errors.Join(r.Errors...)
+}
+
+// Finish 记录结束时间，并为未给出结论的结果补全状态
+func (r *CheckinResult) Finish() *CheckinResult {
+	r.FinishedAt = time.Now()
+	if r.Status == StatusUnknown {
+		r.Fail(errors.New("处理器未返回签到状态"))
+	}
+	return r
+}
+
+// Duration 签到耗时
+func (r *CheckinResult) Duration() time.Duration {
+	if r.FinishedAt.IsZero() {
+		return 0
+	}
+	return r.FinishedAt.Sub(r.StartedAt)
+}
+
+// Lines 返回单个网站的展示信息（随机延迟、处理器信息与失败原因），不含最终状态
+func (r *CheckinResult) Lines() []string {
+	var lines []string
+	if r.Delay > 0 {
+		lines = append(lines, "⏱️ 随机延迟 "+r.Delay.Round(time.Second).String())
+	}
+	lines = append(lines, r.Messages...)
+	if r.Status == StatusFailed {
+		for _, err := range r.Errors {
+			lines = append(lines, "❌ "+err.Error())
+		}
+	}
+	return lines
+}
+
+// Text 渲染单个网站的签到信息
+func (r *CheckinResult) Text() string {
+	var b strings.Builder
+	b.WriteString("👙 [服务]" + r.DisplayName() + "签到信息\n")
+	for _, line := range r.Lines() {
+		b.WriteString("∷∷∷∷" + line + "\n")
+	}
+	b.WriteString("∷∷∷∷" + r.Status.Emoji() + " " + r.Status.Label())
+	return b.String()
+}
+
+// Report 一次签到任务的汇总结果
+type Report struct {
+	Results    []*CheckinResult
+	StartedAt  time.Time
+	FinishedAt time.Time
+}
+
+// Count 统计指定状态的结果数量
+func (rp *Report) Count(status Status) int {
+	n := 0
+	for _, r := range rp.Results {
+		if r.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// Summary 返回各状态数量的一行摘要
+func (rp *Report) Summary() string {
+	ok := rp.Count(StatusSigned) + rp.Count(StatusAlreadySigned)
+	return fmt.Sprintf("成功 %d，失败 %d，跳过 %d", ok, rp.Count(StatusFailed), rp.Count(StatusSkipped))
+}
+
+// Text 渲染签到任务报告
+func (rp *Report) Text() string {
+	var b strings.Builder
+	b.WriteString("\n≡≡≡≡≡≡ 签到任务报告 ≡≡≡≡≡≡\n")
+	for i, r := range rp.Results {
+		b.WriteString("\n" + r.Text() + "\n")
+		if i < len(rp.Results)-1 {
+			b.WriteString("\n—————————————\n")
+		}
+	}
+	b.WriteString("\n≡≡≡≡≡≡ 任务结束 ≡≡≡≡≡≡")
+	return b.String()
+}
@@ -2,10 +2,20 @@ package scheduler
 
 import (
 	"auto-checkin/internal/config"
+	"auto-checkin/internal/cookiestore"
 	"auto-checkin/internal/handler"
+	"auto-checkin/internal/history"
+	"auto-checkin/internal/interfaces"
+	"auto-checkin/internal/ledger"
 	"auto-checkin/internal/logger"
+	"auto-checkin/internal/result"
 	"auto-checkin/internal/util"
+	"auto-checkin/internal/vault"
+	"context"
+	"fmt"
 	"github.com/robfig/cron/v3"
+	"io"
+	"math/rand/v2"
 	"strings"
 	"sync"
 	"time"
@@ -15,71 +25,412 @@ import (
 
 type Scheduler struct {
 	notifier *notifier.Notifier
+	client   util.HTTPDoer // 注入签到处理器的客户端，为空时使用默认客户端
 	ticker   *time.Ticker
 	done     chan bool
+
+	mu      sync.Mutex
+	ctx     context.Context
+	cron    *cron.Cron
+	retired []context.Context // 热加载时停止的定时任务，等待其中正在执行的签到结束
+
+	providersMu sync.Mutex
+	providers   map[string]chan struct{} // 签到服务 -> 执行锁
 }
 
-func New(notifier *notifier.Notifier) *Scheduler {
+// New 创建调度器，client 不为空时所有签到处理器都通过它发送请求
+func New(notifier *notifier.Notifier, client util.HTTPDoer) *Scheduler {
 	return &Scheduler{
 		notifier: notifier,
+		client:   client,
 	}
 }
 
-func (s *Scheduler) Start() {
-	if config.Cfg.Debug {
+// Start 启动签到任务，ctx 取消时停止调度并中止正在执行的签到
+func (s *Scheduler) Start(ctx context.Context) {
+	if cfg := config.Get(); cfg.Debug {
 		// 调试模式只执行一次
-		s.runCheckIn()
+		s.runCheckIn(ctx, cfg, cfg.Websites, false)
 	} else {
-		c := cron.New(cron.WithLocation(util.GetTimeLocation()))
-		_, err := c.AddFunc(config.Cfg.Cron, s.runCheckIn)
+		s.mu.Lock()
+		s.ctx = ctx
+		c, err := s.newCron(cfg)
+		if err == nil {
+			s.cron = c
+			s.cron.Start()
+		}
+		s.mu.Unlock()
 		if err != nil {
 			logger.Log().Error("定时任务配置错误: " + err.Error())
 			return
 		}
-		c.Start()
-		logger.Log().Info("定时任务已启动，执行规则: " + config.Cfg.Cron)
-		select {}
+		<-ctx.Done()
+		logger.Log().Info("收到退出信号，等待正在执行的签到任务结束")
+		s.mu.Lock()
+		stopping := append(s.retired, s.cron.Stop())
+		s.mu.Unlock()
+		for _, done := range stopping {
+			<-done.Done()
+		}
+		logger.Log().Info("定时任务已停止")
+	}
+}
+
+// Reload 热加载配置：先按新配置创建定时任务，全部规则注册成功后才替换全局配置与原有任务，
+// 新规则无效时返回错误并保留原配置。时区随定时任务一起重新创建
+func (s *Scheduler) Reload(c *config.Config) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cron == nil {
+		config.Set(c)
+		return nil
+	}
+	next, err := s.newCron(c)
+	if err != nil {
+		return err
+	}
+	config.Set(c)
+	next.Start()
+	// 原有任务不再触发，正在执行的签到继续完成，退出时一并等待
+	active := s.retired[:0]
+	for _, done := range s.retired {
+		if done.Err() == nil {
+			active = append(active, done)
+		}
+	}
+	s.retired = append(active, s.cron.Stop())
+	s.cron = next
+	return nil
+}
+
+// newCron 按配置的时区创建定时任务，为每个定时规则注册一个任务，调用方需持有 s.mu
+func (s *Scheduler) newCron(cfg *config.Config) (*cron.Cron, error) {
+	c := cron.New(cron.WithLocation(util.TimeLocation(cfg)))
+	for _, group := range cfg.CronGroups() {
+		rule := group.Rule
+		if _, err := c.AddFunc(rule, func() { s.runGroup(s.ctx, rule) }); err != nil {
+			return nil, fmt.Errorf("%s: %v", rule, err)
+		}
+		names := make([]string, 0, len(group.Websites))
+		for _, w := range group.Websites {
+			names = append(names, w.DisplayName())
+		}
+		logger.Log().Infof("定时任务已注册，执行规则: %s，网站: %s", rule, strings.Join(names, ", "))
+	}
+	return c, nil
+}
+
+// runGroup 执行当前配置中使用该定时规则的网站，并推送一份汇总报告
+func (s *Scheduler) runGroup(ctx context.Context, rule string) {
+	cfg := config.Get()
+	for _, group := range cfg.CronGroups() {
+		if group.Rule == rule {
+			s.runCheckIn(ctx, cfg, group.Websites, false)
+			return
+		}
+	}
+}
+
+// Run 使用当前配置立即执行一次指定网站的签到并推送报告，force 为 true 时今日已完成签到的账号也会重新签到
+func (s *Scheduler) Run(ctx context.Context, websites []config.Website, force bool) *result.Report {
+	return s.runCheckIn(ctx, config.Get(), websites, force)
+}
+
+// DryRun 依次试运行指定网站的签到处理器，将构建的请求输出到 out 而不访问网络。
+// 试运行的响应均为空 JSON 对象，不记录签到状态与历史，也不推送报告
+func (s *Scheduler) DryRun(ctx context.Context, websites []config.Website, out io.Writer) {
+	for i, w := range websites {
+		w = w.Clone()
+		if i > 0 {
+			fmt.Fprintln(out)
+		}
+		fmt.Fprintf(out, "%s [%s]\n", w.DisplayName(), w.HandlerName())
+		recorder := util.NewRecorder(out)
+		handle, ok := handler.NewHandler(w.HandlerName(), recorder)
+		if !ok {
+			fmt.Fprintf(out, "  不支持的签到服务: %s\n", w.HandlerName())
+			continue
+		}
+		res := s.runSite(ctx, handle, w)
+		if recorder.Count() == 0 {
+			fmt.Fprintln(out, "  未发送任何请求")
+		}
+		fmt.Fprintf(out, "  处理器返回（基于空响应，仅供参考）: %s\n", res.Status.Label())
 	}
 }
 
-func (s *Scheduler) runCheckIn() {
+// Preview 依次执行指定网站的签到并返回报告，不读取或记录签到状态与历史，也不推送报告。
+// 配合注入的回放客户端可离线验证处理器对录制响应的解析
+func (s *Scheduler) Preview(ctx context.Context, websites []config.Website) *result.Report {
+	report := &result.Report{StartedAt: time.Now()}
+	for _, w := range websites {
+		w = w.Clone()
+		handle, ok := handler.NewHandler(w.HandlerName(), s.client)
+		if !ok {
+			res := result.New(w.Name, w.Account)
+			res.Status = result.StatusSkipped
+			res.Push("❌ 不支持的签到服务: %s", w.HandlerName())
+			report.Results = append(report.Results, res.Finish())
+			continue
+		}
+		report.Results = append(report.Results, s.runSite(ctx, handle, w))
+	}
+	report.FinishedAt = time.Now()
+	return report
+}
+
+// runCheckIn 执行一组网站的签到，整个任务使用同一份配置快照，执行期间的热加载不影响本次任务
+func (s *Scheduler) runCheckIn(ctx context.Context, cfg *config.Config, websites []config.Website, force bool) *result.Report {
 	logger.Log().Info("开始签到任务")
-	var wg sync.WaitGroup
-	var handlers []string
-	for h, _ := range handler.CheckinHandlers {
-		handlers = append(handlers, h)
+	ctx, cancel := context.WithTimeout(ctx, cfg.RunDeadline())
+	defer cancel()
+
+	logger.Log().Debugf("当前注册的处理器: %+v", handler.HandlerNames())
+
+	// 加载当天签到状态，已完成签到的账号不再重复执行
+	today := util.Today()
+	state, err := ledger.Open(cfg.DataPath(ledger.FileName))
+	if err != nil {
+		logger.Log().Errorf("签到状态加载失败: %v", err)
+	}
+	// 加载上次签到后保存的会话 Cookie
+	cookies, err := s.openCookies(cfg)
+	if err != nil {
+		logger.Log().Errorf("会话 Cookie 加载失败: %v", err)
 	}
-	logger.Log().Debugf("当前注册的处理器: %+v", handlers)
 
-	var signRes []string
-	for index, website := range config.Cfg.Websites {
+	report := &result.Report{
+		Results:   make([]*result.CheckinResult, len(websites)),
+		StartedAt: time.Now(),
+	}
+	job := &job{
+		cfg:       cfg,
+		startedAt: report.StartedAt,
+		sem:       make(chan struct{}, cfg.MaxConcurrency(len(websites))),
+		state:     state,
+		cookies:   cookies,
+		today:     today,
+		force:     force,
+	}
+	var wg sync.WaitGroup
+	for _, lane := range lanes(cfg, websites) {
 		wg.Add(1)
-		go func(i int, w config.Website) {
+		go func(lane []int) {
 			defer wg.Done()
-			handle, ok := handler.CheckinHandlers[strings.ToLower(w.Name)]
-			if !ok {
-				signRes = append(signRes, "\n[服务] "+w.Name+"\n❌ 不支持的签到服务: "+w.Name+"\n")
-				logger.Log().Info("不支持的签到服务: " + w.Name)
-			} else {
-				logger.Log().Info("开始签到: " + w.Name)
-				m := handle.Run(w)
-				if m != "" {
-					signRes = append(signRes, "\n"+m)
-				}
-				logger.Log().Info("签到完成: " + w.Name)
+			// 同一队列中的网站按配置顺序依次签到
+			for _, i := range lane {
+				report.Results[i] = s.checkIn(ctx, job, websites[i].Clone())
 			}
-
-		}(index, website)
+		}(lane)
 	}
-	signContent := "\n≡≡≡≡≡≡ 签到任务报告 ≡≡≡≡≡≡\n"
 	wg.Wait()
-	for i, re := range signRes {
-		signContent += re
-		if i < len(signRes)-1 {
-			signContent += "\n—————————————\n"
+	report.FinishedAt = time.Now()
+	s.saveHistory(cfg, report)
+	if err := state.Save(); err != nil {
+		logger.Log().Errorf("签到状态保存失败: %v", err)
+	}
+	if err := cookies.Save(); err != nil {
+		logger.Log().Errorf("会话 Cookie 保存失败: %v", err)
+	}
+	if report.Count(result.StatusSkipped) == len(report.Results) {
+		logger.Log().Info("所有网站均已跳过，不推送签到报告")
+		return report
+	}
+	logger.Log().Debug(report.Text())
+	_ = s.notifier.Push(ctx, report)
+	return report
+}
+
+// openCookies 打开会话 Cookie 存储，已创建凭据库时使用凭据库密钥加密保存。
+// 凭据库无法打开时返回空存储，本次任务不读取也不保存会话 Cookie，避免以明文写入磁盘
+func (s *Scheduler) openCookies(cfg *config.Config) (*cookiestore.Store, error) {
+	var v *vault.Vault
+	if cfg.VaultExists() {
+		var err error
+		if v, err = cfg.OpenVault(); err != nil {
+			return nil, fmt.Errorf("凭据库打开失败，本次不保存会话 Cookie: %v", err)
+		}
+	}
+	return cookiestore.Open(cfg.DataPath(cookiestore.FileName), v)
+}
+
+// lanes 将网站划分为执行队列：顺序模式下只有一个队列，否则同一服务的账号位于同一队列，避免并发签到
+func lanes(cfg *config.Config, websites []config.Website) [][]int {
+	if cfg.Sequential {
+		lane := make([]int, len(websites))
+		for i := range websites {
+			lane[i] = i
 		}
+		return [][]int{lane}
+	}
+	var out [][]int
+	index := make(map[string]int)
+	for i, w := range websites {
+		if n, ok := index[w.Provider()]; ok {
+			out[n] = append(out[n], i)
+			continue
+		}
+		index[w.Provider()] = len(out)
+		out = append(out, []int{i})
+	}
+	return out
+}
+
+// job 一次签到任务中各网站共享的状态
+type job struct {
+	cfg       *config.Config
+	startedAt time.Time
+	sem       chan struct{} // 并发名额
+	state     *ledger.Ledger
+	cookies   *cookiestore.Store
+	today     string
+	force     bool // 忽略当天签到状态，已完成签到的账号也重新签到
+}
+
+// checkIn 执行单个网站的签到：随机延迟、获取服务锁与并发名额后调用处理器
+func (s *Scheduler) checkIn(ctx context.Context, job *job, w config.Website) *result.CheckinResult {
+	// 每个账号使用独立的处理器实例与配置副本
+	handle, ok := handler.NewHandler(w.HandlerName(), s.client)
+	if !ok {
+		res := result.New(w.Name, w.Account)
+		res.Status = result.StatusSkipped
+		res.Push("❌ 不支持的签到服务: %s", w.HandlerName())
+		logger.Log().Info("不支持的签到服务: " + w.HandlerName())
+		return res.Finish()
+	}
+	if !job.force && job.state.Done(w.Key(), job.today) {
+		res := result.New(w.Name, w.Account)
+		res.Status = result.StatusSkipped
+		res.Push("⏭️ 今日已完成签到，跳过")
+		logger.Log().Info("今日已完成签到，跳过: " + w.DisplayName())
+		return res.Finish()
+	}
+
+	abort := func(delay time.Duration, err error) *result.CheckinResult {
+		res := result.New(w.Name, w.Account)
+		res.Delay = delay
+		res.Fail(fmt.Errorf("签到中止: %v", err))
+		logger.Log().Errorf("[%s]等待执行时中止: %v", w.DisplayName(), err)
+		return res.Finish()
+	}
+	// 延迟从任务开始时计算，排队等待的时间计入延迟，不会累加
+	delay := jitter(job.cfg.JitterWindow(w))
+	if err := waitUntil(ctx, job.startedAt.Add(delay)); err != nil {
+		return abort(delay, err)
+	}
+	// 同一服务的账号在所有定时任务之间串行执行
+	unlock, err := s.lockProvider(ctx, w.Provider())
+	if err != nil {
+		return abort(delay, err)
+	}
+	defer unlock()
+	select {
+	case job.sem <- struct{}{}:
+		defer func() { <-job.sem }()
+	case <-ctx.Done():
+		return abort(delay, ctx.Err())
+	}
+
+	// 恢复上次保存的会话 Cookie，签到后记录服务端更新的 Cookie
+	session := util.SessionFor(w.Key())
+	job.cookies.Restore(w.Key(), session)
+	logger.Log().Info("开始签到: " + w.DisplayName())
+	res := s.runSite(ctx, handle, w)
+	res.Delay = delay
+	if names := session.TakeChanged(); len(names) > 0 {
+		res.Push("🍪 Cookie 已更新: %s", strings.Join(names, ", "))
+		logger.Log().Infof("[%s]服务端更新了 Cookie: %s", w.DisplayName(), strings.Join(names, ", "))
+	}
+	job.cookies.Put(w.Key(), session)
+	job.state.Mark(w.Key(), job.today, res.Status)
+	logger.Log().Infof("签到完成: %s [%s]", w.DisplayName(), res.Status)
+	return res
+}
+
+// lockProvider 获取签到服务的执行锁，返回释放函数
+func (s *Scheduler) lockProvider(ctx context.Context, name string) (func(), error) {
+	s.providersMu.Lock()
+	if s.providers == nil {
+		s.providers = make(map[string]chan struct{})
+	}
+	lock, ok := s.providers[name]
+	if !ok {
+		lock = make(chan struct{}, 1)
+		s.providers[name] = lock
+	}
+	s.providersMu.Unlock()
+
+	select {
+	case lock <- struct{}{}:
+		return func() { <-lock }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// runSite 在网站超时时间内执行签到，超时或取消时不再等待处理器返回
+func (s *Scheduler) runSite(ctx context.Context, handle interfaces.Logic, w config.Website) *result.CheckinResult {
+	ctx, cancel := context.WithTimeout(ctx, w.SiteTimeout())
+	defer cancel()
+
+	startedAt := time.Now()
+	done := make(chan *result.CheckinResult, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.Log().Errorf("[%s]签到异常: %v", w.DisplayName(), r)
+				res := result.New(w.Name, w.Account)
+				res.StartedAt = startedAt
+				res.Fail(fmt.Errorf("签到异常: %v", r))
+				done <- res.Finish()
+			}
+		}()
+		done <- handle.Run(ctx, w)
+	}()
+
+	select {
+	case res := <-done:
+		return res
+	case <-ctx.Done():
+		logger.Log().Errorf("[%s]签到中止: %v", w.DisplayName(), ctx.Err())
+		res := result.New(w.Name, w.Account)
+		res.StartedAt = startedAt
+		res.Fail(fmt.Errorf("签到中止: %v", ctx.Err()))
+		return res.Finish()
+	}
+}
+
+// saveHistory 记录本次签到的历史
+func (s *Scheduler) saveHistory(cfg *config.Config, report *result.Report) {
+	records := make([]history.Record, 0, len(report.Results))
+	for _, res := range report.Results {
+		records = append(records, history.FromResult(res))
+	}
+	if err := history.Open(cfg.DataPath(history.FileName)).Append(records...); err != nil {
+		logger.Log().Errorf("签到历史保存失败: %v", err)
+	}
+}
+
+// jitter 在 [0, window) 内随机选取延迟
+func jitter(window time.Duration) time.Duration {
+	if window <= 0 {
+		return 0
+	}
+	return rand.N(window)
+}
+
+// waitUntil 等待到指定时间，ctx 取消时提前返回
+func waitUntil(ctx context.Context, t time.Time) error {
+	d := time.Until(t)
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
 	}
-	signContent += "\n≡≡≡≡≡≡ 任务结束 ≡≡≡≡≡≡"
-	logger.Log().Debug(signContent)
-	s.notifier.Push(signContent)
 }
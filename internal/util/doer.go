@@ -0,0 +1,17 @@
+package util
+
+import (
+	"net/http"
+)
+
+// HTTPDoer 发送 HTTP 请求的客户端，*http.Client 即满足该接口
+type HTTPDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// TransportWrapper 包装传输层的客户端。作为 RequestParams.Client 注入时仍按请求参数新建客户端，
+// 代理、TLS、超时与 Cookie Jar 等设置与真实请求一致，只在客户端的传输层外再包装一层
+type TransportWrapper interface {
+	HTTPDoer
+	WrapTransport(next http.RoundTripper) http.RoundTripper
+}
@@ -0,0 +1,33 @@
+package util
+
+import (
+	"strconv"
+	"strings"
+)
+
+// JSONPath 按点分隔的路径读取 JSON 解码后的数据，数组使用数字下标，如 data.list.0.balance
+func JSONPath(data any, path string) (any, bool) {
+	if path == "" || path == "." {
+		return data, true
+	}
+	current := data
+	for _, key := range strings.Split(strings.TrimPrefix(path, "."), ".") {
+		switch v := current.(type) {
+		case map[string]any:
+			next, ok := v[key]
+			if !ok {
+				return nil, false
+			}
+			current = next
+		case []any:
+			idx, err := strconv.Atoi(key)
+			if err != nil || idx < 0 || idx >= len(v) {
+				return nil, false
+			}
+			current = v[idx]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
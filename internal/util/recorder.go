@@ -0,0 +1,180 @@
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// sensitiveWords 请求头、查询参数或请求体字段的名称包含这些词时视为敏感信息
+var sensitiveWords = []string{
+	"cookie", "token", "auth", "secret", "key", "sign", "session", "password", "passwd", "ticket",
+	"kps", "vcode", "h5st", "uuid", "pin",
+}
+
+// Recorder 试运行客户端：打印完整构建的请求而不访问网络，并返回空 JSON 对象作为响应
+type Recorder struct {
+	mu    sync.Mutex
+	out   io.Writer
+	count int
+}
+
+// NewRecorder 创建将请求输出到 out 的试运行客户端
+func NewRecorder(out io.Writer) *Recorder {
+	return &Recorder{out: out}
+}
+
+// Count 返回已记录的请求数
+func (r *Recorder) Count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.count
+}
+
+// Do 记录请求，响应固定为 200 与 {}
+func (r *Recorder) Do(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		_ = req.Body.Close()
+	}
+
+	r.mu.Lock()
+	r.count++
+	var b strings.Builder
+	fmt.Fprintf(&b, "  [%d] %s %s\n", r.count, req.Method, maskURL(req.URL))
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range req.Header[name] {
+			if sensitiveName(name) {
+				value = MaskSecret(value)
+			}
+			fmt.Fprintf(&b, "      %s: %s\n", name, value)
+		}
+	}
+	if len(body) > 0 {
+		fmt.Fprintf(&b, "      %s\n", maskBody(body, req.Header.Get("Content-Type")))
+	}
+	_, _ = io.WriteString(r.out, b.String())
+	r.mu.Unlock()
+
+	return &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(strings.NewReader("{}")),
+		Request:    req,
+	}, nil
+}
+
+func sensitiveName(name string) bool {
+	name = strings.ToLower(name)
+	for _, word := range sensitiveWords {
+		if strings.Contains(name, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// maskQuery 遮盖 a=1&b=2 形式参数中的敏感值，保持参数顺序，遮盖后的值不再编码以便阅读
+func maskQuery(query string) string {
+	pairs := strings.Split(query, "&")
+	for i, pair := range pairs {
+		name, value, found := strings.Cut(pair, "=")
+		key, err := url.QueryUnescape(name)
+		if err != nil {
+			key = name
+		}
+		if !found || !sensitiveName(key) {
+			continue
+		}
+		if v, err := url.QueryUnescape(value); err == nil {
+			value = v
+		}
+		pairs[i] = name + "=" + MaskSecret(value)
+	}
+	return strings.Join(pairs, "&")
+}
+
+// maskURL 返回遮盖了敏感查询参数的地址
+func maskURL(u *url.URL) string {
+	if u.RawQuery == "" {
+		return u.String()
+	}
+	out := *u
+	out.RawQuery = ""
+	return out.String() + "?" + maskQuery(u.RawQuery)
+}
+
+// maskBody 遮盖请求体中的敏感字段，支持表单与 JSON，其他格式原样返回
+func maskBody(body []byte, contentType string) string {
+	if strings.Contains(contentType, "application/x-www-form-urlencoded") {
+		return maskQuery(string(body))
+	}
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 || (trimmed[0] != '{' && trimmed[0] != '[') {
+		return string(body)
+	}
+	dec := json.NewDecoder(bytes.NewReader(trimmed))
+	dec.UseNumber()
+	var data any
+	if err := dec.Decode(&data); err != nil {
+		return string(body)
+	}
+	var out bytes.Buffer
+	enc := json.NewEncoder(&out)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(maskJSON(data, false)); err != nil {
+		return string(body)
+	}
+	return strings.TrimSpace(out.String())
+}
+
+// maskJSON 递归遮盖 JSON 中敏感字段的值，sensitive 表示所在字段本身为敏感字段
+func maskJSON(v any, sensitive bool) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			v[key] = maskJSON(value, sensitive || sensitiveName(key))
+		}
+		return v
+	case []any:
+		for i, value := range v {
+			v[i] = maskJSON(value, sensitive)
+		}
+		return v
+	case nil, bool:
+		return v
+	default:
+		if sensitive {
+			return MaskSecret(fmt.Sprint(v))
+		}
+		return v
+	}
+}
+
+// MaskSecret 隐藏敏感值，只保留前 4 个字符与长度
+func MaskSecret(s string) string {
+	r := []rune(s)
+	if len(r) <= 8 {
+		return "****"
+	}
+	return fmt.Sprintf("%s****（共 %d 字符）", string(r[:4]), len(r))
+}
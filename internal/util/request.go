@@ -13,10 +13,12 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 )
 
 type RequestParams struct {
+	Context            context.Context
 	Method             string
 	URL                string
 	QueryParams        map[string]string
@@ -27,27 +29,63 @@ type RequestParams struct {
 	InsecureSkipVerify bool
 	Timeout            int
 	Proxy              bool
+	Retry              *config.Retry // 重试策略，为空时不重试
+	Client             HTTPDoer      // 发送请求的客户端，为空时按请求参数新建；实现 TransportWrapper 时按请求参数新建并包装其传输层
+	Session            *Session      // 账号会话，不为空时由会话合并 Headers 中的 Cookie 并保存服务端下发的 Cookie
+	AllowNonJSON       bool          // 响应体不是 JSON（包括空响应体）时不视为失败，返回空结果
+	RawBody            bool          // 不解析响应体，以 {"body": 响应文本} 返回，用于 HTML、纯文本响应
 }
 
-// createHTTPClient 创建HTTP客户端
-func createHTTPClient(insecureSkipVerify bool, timeout int, proxy bool) *http.Client {
-	transport := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: insecureSkipVerify},
+// transportKey 共享连接池的标识
+type transportKey struct {
+	insecureSkipVerify bool
+	proxy              string
+}
+
+var (
+	transportsMu sync.Mutex
+	transports   = make(map[transportKey]*http.Transport)
+)
+
+// sharedTransport 返回相同 TLS 与代理设置共用的连接池，复用 keep-alive 连接与 TLS 会话
+func sharedTransport(insecureSkipVerify bool, proxy string) (*http.Transport, error) {
+	key := transportKey{insecureSkipVerify: insecureSkipVerify, proxy: proxy}
+	transportsMu.Lock()
+	defer transportsMu.Unlock()
+	if t, ok := transports[key]; ok {
+		return t, nil
 	}
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.Proxy = nil
+	t.TLSClientConfig = &tls.Config{InsecureSkipVerify: insecureSkipVerify}
+	if proxy != "" {
+		parse, err := url.Parse(proxy)
+		if err != nil {
+			return nil, err
+		}
+		t.Proxy = http.ProxyURL(parse)
+	}
+	transports[key] = t
+	return t, nil
+}
+
+// createHTTPClient 创建使用共享连接池的HTTP客户端，jar 不为空时自动处理 Set-Cookie 与重定向中的 Cookie
+func createHTTPClient(insecureSkipVerify bool, timeout int, proxy bool, jar http.CookieJar) *http.Client {
+	proxyURL := ""
 	if true == proxy {
-		if config.Cfg.Proxy.Host != "" && config.Cfg.Proxy.Port != "" {
-			proxyURL := fmt.Sprintf("%s:%s", config.Cfg.Proxy.Host, config.Cfg.Proxy.Port)
-			parse, err := url.Parse(proxyURL)
-			if err != nil {
-				logger.Log().Errorf("parse proxy error: %v", err)
-				return nil
-			}
-			transport.Proxy = http.ProxyURL(parse)
+		if config.Get().Proxy.Host != "" && config.Get().Proxy.Port != "" {
+			proxyURL = fmt.Sprintf("%s:%s", config.Get().Proxy.Host, config.Get().Proxy.Port)
 		}
 	}
+	transport, err := sharedTransport(insecureSkipVerify, proxyURL)
+	if err != nil {
+		logger.Log().Errorf("parse proxy error: %v", err)
+		return nil
+	}
 
 	client := &http.Client{
 		Transport: transport,
+		Jar:       jar,
 	}
 	if timeout <= 0 {
 		client.Timeout = time.Duration(30) * time.Second
@@ -111,9 +149,9 @@ func createRequestBody(bodyData interface{}, bodyToJson bool, bodyToFormData boo
 	}
 }
 
-// setContentType 根据BodyData类型设置Content-Type
+// setContentType 根据BodyData类型设置Content-Type，请求头中已指定时保持不变
 func setContentType(request *http.Request, bodyData interface{}) {
-	if bodyData == nil {
+	if bodyData == nil || request.Header.Get("Content-Type") != "" {
 		return
 	}
 
@@ -127,18 +165,69 @@ func setContentType(request *http.Request, bodyData interface{}) {
 	}
 }
 
+// SendRequest 发送请求并解析 JSON 响应，按 Retry 策略重试临时性失败
 func SendRequest(req *RequestParams) (map[string]interface{}, error) {
-	client := createHTTPClient(req.InsecureSkipVerify, req.Timeout, req.Proxy)
 	urlWithQuery, err := buildURL(req.URL, req.QueryParams)
 	if err != nil {
 		return nil, err
 	}
+	ctx := req.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	client := req.Client
+	wrapper, wrap := client.(TransportWrapper)
+	// 默认客户端直接使用会话的 Cookie Jar，注入的客户端由 sendOnce 手动维护 Cookie
+	manualJar := client != nil && !wrap
+	if client == nil || wrap {
+		var jar http.CookieJar
+		if req.Session != nil {
+			jar = req.Session.Jar()
+		}
+		c := createHTTPClient(req.InsecureSkipVerify, req.Timeout, req.Proxy, jar)
+		if c == nil {
+			return nil, fmt.Errorf("failed to create HTTP client")
+		}
+		if wrap {
+			c.Transport = wrapper.WrapTransport(c.Transport)
+		}
+		client = c
+	}
+
+	attempts := maxAttempts(req.Retry)
+	for attempt := 1; ; attempt++ {
+		result, err := sendOnce(ctx, client, manualJar, req, urlWithQuery)
+		if err == nil {
+			return result, nil
+		}
+		var re *retryableError
+		if attempt >= attempts || !errors.As(err, &re) || ctx.Err() != nil {
+			return nil, err
+		}
+		delay := backoff(req.Retry, attempt)
+		if re.after > delay {
+			delay = re.after
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Now().Add(delay).After(deadline) {
+			return nil, err
+		}
+		logger.Log().Warnf("请求失败，%v 后进行第 %d 次重试: %v", delay, attempt+1, err)
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(delay):
+		}
+	}
+}
+
+// sendOnce 发送一次请求
+func sendOnce(ctx context.Context, client HTTPDoer, manualJar bool, req *RequestParams, urlWithQuery string) (map[string]interface{}, error) {
 	bodyData, err := createRequestBody(req.BodyData, req.BodyToJson, req.BodyToFormData)
 	if err != nil {
 		return nil, err
 	}
 
-	request, err := http.NewRequest(req.Method, urlWithQuery, bodyData)
+	request, err := http.NewRequestWithContext(ctx, req.Method, urlWithQuery, bodyData)
 	if err != nil {
 		return nil, err
 	}
@@ -148,32 +237,55 @@ func SendRequest(req *RequestParams) (map[string]interface{}, error) {
 			request.Header.Add(key, value)
 		}
 	}
+	if req.Session != nil {
+		// 会话接管 Cookie：请求自带的 Cookie 与会话中的 Cookie 合并为一个请求头
+		cookie := req.Session.header(request.URL, strings.Join(request.Header.Values("Cookie"), "; "), manualJar)
+		request.Header.Del("Cookie")
+		if cookie != "" {
+			request.Header.Set("Cookie", cookie)
+		}
+	}
 	setContentType(request, req.BodyData)
 	logger.Log().Debug("正在发送请求Request URL: ", urlWithQuery)
 	resp, err := client.Do(request)
 	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			return nil, fmt.Errorf("request canceled: %v", err)
+		}
 		if errors.Is(err, context.DeadlineExceeded) || strings.Contains(err.Error(), "Client.Timeout exceeded") {
-			return nil, fmt.Errorf("request timeout: %v", err)
+			return nil, &retryableError{err: fmt.Errorf("request timeout: %v", err)}
 		}
-		return nil, fmt.Errorf("failed to send HTTP request: %v", err)
+		return nil, &retryableError{err: fmt.Errorf("failed to send HTTP request: %v", err)}
 	}
 	defer resp.Body.Close()
+	if req.Session != nil && manualJar {
+		req.Session.SetCookies(request.URL, resp.Cookies())
+	}
 
 	if resp.StatusCode >= 400 {
-		return nil, fmt.Errorf("HTTP request failed with status code: %d  for URL: %s", resp.StatusCode, urlWithQuery)
+		err := fmt.Errorf("HTTP request failed with status code: %d  for URL: %s", resp.StatusCode, urlWithQuery)
+		if retryableStatus(req.Retry, resp.StatusCode) {
+			return nil, &retryableError{err: err, after: retryAfter(resp.Header.Get("Retry-After"))}
+		}
+		return nil, err
 	}
 
 	// 读取响应体
 	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
-		fmt.Println("Error reading response body:", err)
-		return nil, nil
+		return nil, &retryableError{err: fmt.Errorf("failed to read response body: %v", err)}
 	}
 	// 打印响应体内容
 	bodyString := string(bodyBytes)
 	logger.Log().Debugf("%s - Response Body: %s", urlWithQuery, bodyString)
+	if req.RawBody {
+		return map[string]interface{}{"body": bodyString}, nil
+	}
 	var result map[string]interface{}
 	if err = json.Unmarshal(bodyBytes, &result); err != nil {
+		if req.AllowNonJSON {
+			return map[string]interface{}{}, nil
+		}
 		return nil, fmt.Errorf("failed to decode response body: %v", err)
 	}
 	return result, nil
@@ -0,0 +1,83 @@
+package util
+
+import (
+	"auto-checkin/internal/config"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// retryableError 可重试的请求错误
+type retryableError struct {
+	err   error
+	after time.Duration // 服务端通过 Retry-After 要求的等待时间
+}
+
+func (e *retryableError) Error() string {
+	return e.err.Error()
+}
+
+func (e *retryableError) Unwrap() error {
+	return e.err
+}
+
+// maxAttempts 最大尝试次数（含首次请求）
+func maxAttempts(policy *config.Retry) int {
+	if policy == nil || policy.MaxAttempts < 1 {
+		return 1
+	}
+	return policy.MaxAttempts
+}
+
+// retryableStatus 判断状态码是否需要重试
+func retryableStatus(policy *config.Retry, code int) bool {
+	if policy == nil {
+		return false
+	}
+	if len(policy.RetryOn) > 0 {
+		return slices.Contains(policy.RetryOn, code)
+	}
+	return slices.Contains(config.DefaultRetryOn, code)
+}
+
+// backoff 计算第 attempt 次失败后的等待时间（指数退避 + 随机抖动）
+func backoff(policy *config.Retry, attempt int) time.Duration {
+	base := float64(config.DefaultRetryBackoff)
+	limit := float64(config.DefaultRetryMaxBackoff)
+	jitter := config.DefaultRetryJitter
+	if policy != nil {
+		if policy.Backoff > 0 {
+			base = float64(policy.Backoff)
+		}
+		if policy.MaxBackoff > 0 {
+			limit = float64(policy.MaxBackoff)
+		}
+		if policy.Jitter != nil {
+			jitter = *policy.Jitter
+		}
+	}
+	delay := math.Min(base*math.Pow(2, float64(attempt-1)), limit)
+	if jitter > 0 {
+		delay += delay * jitter * (rand.Float64()*2 - 1)
+	}
+	return time.Duration(delay) * time.Millisecond
+}
+
+// retryAfter 解析 Retry-After 响应头，支持秒数和 HTTP 日期两种格式
+func retryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
@@ -0,0 +1,273 @@
+package util
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Session 账号级 HTTP 会话：配置中的 Cookie 作为种子随每个请求发送，
+// 服务端通过 Set-Cookie 下发的 Cookie 保存在 Cookie Jar 中，同名时优先于种子
+type Session struct {
+	mu       sync.Mutex
+	seeds    []*http.Cookie
+	seedHash string // 当前 Jar 所基于的种子摘要，种子变化时清空 Jar
+	jar      *cookiejar.Jar
+	saved    map[string]SavedCookie // 可持久化的 Jar 内容
+	changed  map[string]bool        // 上次 TakeChanged 之后更新过的 Cookie 名称
+	restored bool
+}
+
+// SavedCookie 持久化保存的服务端 Cookie
+type SavedCookie struct {
+	URL      string    `json:"url"`
+	Name     string    `json:"name"`
+	Value    string    `json:"value"`
+	Path     string    `json:"path,omitempty"`
+	Domain   string    `json:"domain,omitempty"`
+	Expires  time.Time `json:"expires"`
+	Secure   bool      `json:"secure,omitempty"`
+	HttpOnly bool      `json:"http_only,omitempty"`
+}
+
+var (
+	sessionsMu sync.Mutex
+	sessions   = make(map[string]*Session)
+)
+
+// NewSession 创建空会话
+func NewSession() *Session {
+	jar, _ := cookiejar.New(nil)
+	return &Session{
+		jar:     jar,
+		saved:   make(map[string]SavedCookie),
+		changed: make(map[string]bool),
+	}
+}
+
+// SessionFor 返回账号的会话，同一进程内相同标识共享一个会话，多次签到之间保留服务端下发的 Cookie
+func SessionFor(key string) *Session {
+	sessionsMu.Lock()
+	defer sessionsMu.Unlock()
+	s, ok := sessions[key]
+	if !ok {
+		s = NewSession()
+		sessions[key] = s
+	}
+	return s
+}
+
+// Seed 替换种子 Cookie，headerCookie 为 Cookie 请求头的原始值，cookies 为按名称配置的 Cookie（同名时优先）
+func (s *Session) Seed(headerCookie string, cookies map[string]string) {
+	seeds := ParseCookieHeader(headerCookie)
+	names := make([]string, 0, len(cookies))
+	for name := range cookies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		seeds = setCookie(seeds, &http.Cookie{Name: name, Value: cookies[name]})
+	}
+	hash := seedHash(seeds)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seeds = seeds
+	// 配置中的 Cookie 被修改（如手动更换了过期的 Cookie）时，之前保存的服务端 Cookie 不再可信
+	if s.seedHash != "" && s.seedHash != hash && len(s.saved) > 0 {
+		s.jar, _ = cookiejar.New(nil)
+		s.saved = make(map[string]SavedCookie)
+	}
+	s.seedHash = hash
+}
+
+// seedHash 计算种子 Cookie 的摘要，只用于判断配置是否变化
+func seedHash(seeds []*http.Cookie) string {
+	h := sha256.New()
+	for _, c := range seeds {
+		h.Write([]byte(c.Name + "=" + c.Value + "\n"))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Jar 返回记录服务端 Cookie 的 Cookie Jar
+func (s *Session) Jar() http.CookieJar {
+	return s
+}
+
+// Cookies 实现 http.CookieJar
+func (s *Session) Cookies(u *url.URL) []*http.Cookie {
+	s.mu.Lock()
+	jar := s.jar
+	s.mu.Unlock()
+	return jar.Cookies(u)
+}
+
+// SetCookies 实现 http.CookieJar，同时记录可持久化的内容与发生变化的 Cookie
+func (s *Session) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	if len(cookies) == 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	for _, c := range cookies {
+		key := savedKey(u, c)
+		prev, existed := s.saved[key]
+		if c.MaxAge < 0 || (!c.Expires.IsZero() && c.Expires.Before(now)) {
+			if existed {
+				delete(s.saved, key)
+				s.changed[c.Name] = true
+			}
+			continue
+		}
+		if !existed || prev.Value != c.Value {
+			s.changed[c.Name] = true
+		}
+		expires := c.Expires
+		if c.MaxAge > 0 {
+			expires = now.Add(time.Duration(c.MaxAge) * time.Second)
+		}
+		s.saved[key] = SavedCookie{
+			URL:      u.Scheme + "://" + u.Host + u.Path,
+			Name:     c.Name,
+			Value:    c.Value,
+			Path:     c.Path,
+			Domain:   c.Domain,
+			Expires:  expires,
+			Secure:   c.Secure,
+			HttpOnly: c.HttpOnly,
+		}
+	}
+	s.jar.SetCookies(u, cookies)
+}
+
+// savedKey 持久化 Cookie 的标识：域名 + 路径 + 名称
+func savedKey(u *url.URL, c *http.Cookie) string {
+	domain := strings.TrimPrefix(strings.ToLower(c.Domain), ".")
+	if domain == "" {
+		domain = u.Hostname()
+	}
+	return domain + c.Path + ";" + c.Name
+}
+
+// TakeChanged 返回上次调用之后被服务端更新的 Cookie 名称（已排序），并清空记录
+func (s *Session) TakeChanged() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	names := make([]string, 0, len(s.changed))
+	for name := range s.changed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	s.changed = make(map[string]bool)
+	return names
+}
+
+// Snapshot 返回需要持久化的种子摘要与未过期的服务端 Cookie
+func (s *Session) Snapshot() (string, []SavedCookie) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	keys := make([]string, 0, len(s.saved))
+	for key, c := range s.saved {
+		if !c.Expires.IsZero() && c.Expires.Before(now) {
+			delete(s.saved, key)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	cookies := make([]SavedCookie, 0, len(keys))
+	for _, key := range keys {
+		cookies = append(cookies, s.saved[key])
+	}
+	return s.seedHash, cookies
+}
+
+// Restore 将持久化的服务端 Cookie 恢复到会话中，每个会话只恢复一次
+func (s *Session) Restore(hash string, cookies []SavedCookie) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.restored {
+		return
+	}
+	s.restored = true
+	s.seedHash = hash
+	now := time.Now()
+	for _, saved := range cookies {
+		u, err := url.Parse(saved.URL)
+		if err != nil || (!saved.Expires.IsZero() && saved.Expires.Before(now)) {
+			continue
+		}
+		c := &http.Cookie{
+			Name:     saved.Name,
+			Value:    saved.Value,
+			Path:     saved.Path,
+			Domain:   saved.Domain,
+			Expires:  saved.Expires,
+			Secure:   saved.Secure,
+			HttpOnly: saved.HttpOnly,
+		}
+		s.jar.SetCookies(u, []*http.Cookie{c})
+		s.saved[savedKey(u, c)] = saved
+	}
+}
+
+// header 返回发往 u 的 Cookie 请求头，extra 为请求自带的 Cookie 请求头，同名时优先于种子。
+// withJar 为 false 时只包含未被 Jar 覆盖的 Cookie，Jar 中的 Cookie 由 http.Client 自行添加
+func (s *Session) header(u *url.URL, extra string, withJar bool) string {
+	s.mu.Lock()
+	cookies := append([]*http.Cookie(nil), s.seeds...)
+	s.mu.Unlock()
+	for _, c := range ParseCookieHeader(extra) {
+		cookies = setCookie(cookies, c)
+	}
+
+	fromJar := s.Cookies(u)
+	overridden := make(map[string]bool, len(fromJar))
+	for _, c := range fromJar {
+		overridden[c.Name] = true
+	}
+	parts := make([]string, 0, len(cookies)+len(fromJar))
+	for _, c := range cookies {
+		if !overridden[c.Name] {
+			parts = append(parts, c.Name+"="+c.Value)
+		}
+	}
+	if withJar {
+		for _, c := range fromJar {
+			parts = append(parts, c.Name+"="+c.Value)
+		}
+	}
+	return strings.Join(parts, "; ")
+}
+
+// ParseCookieHeader 宽松解析 Cookie 请求头，保留原始值不做转义
+func ParseCookieHeader(line string) []*http.Cookie {
+	var cookies []*http.Cookie
+	for _, part := range strings.Split(line, ";") {
+		name, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		if name = strings.TrimSpace(name); name != "" {
+			cookies = setCookie(cookies, &http.Cookie{Name: name, Value: strings.TrimSpace(value)})
+		}
+	}
+	return cookies
+}
+
+// setCookie 添加或替换同名 Cookie，保持原有顺序
+func setCookie(cookies []*http.Cookie, c *http.Cookie) []*http.Cookie {
+	for i, existing := range cookies {
+		if existing.Name == c.Name {
+			cookies[i] = c
+			return cookies
+		}
+	}
+	return append(cookies, c)
+}
@@ -1,17 +1,28 @@
 package util
 
 import (
+	"auto-checkin/internal/config"
 	"auto-checkin/internal/logger"
 	"time"
 )
 
+// GetTimeLocation 返回配置的时区，未配置时使用 Asia/Shanghai
 func GetTimeLocation() *time.Location {
-	loc, err := time.LoadLocation("Asia/Shanghai")
+	return TimeLocation(config.Get())
+}
+
+// TimeLocation 返回指定配置的时区，用于热加载时在替换全局配置前创建定时任务
+func TimeLocation(c *config.Config) *time.Location {
+	name := c.Timezone
+	if name == "" {
+		name = config.DefaultTimezone
+	}
+	loc, err := time.LoadLocation(name)
 	if err != nil {
 		logger.Log().Error("时区加载失败: " + err.Error())
-		return loc
+		return time.Local
 	}
-	return time.Local
+	return loc
 }
 
 func GetNowUnixTimestamp() int64 {
@@ -29,3 +40,8 @@ func GetMilliTimestamp() int64 {
 	milliTimestamp := nanoTimestamp / 1_000_000
 	return milliTimestamp
 }
+
+// Today 返回指定时区当天的日期字符串
+func Today() string {
+	return time.Now().In(GetTimeLocation()).Format(time.DateOnly)
+}
@@ -0,0 +1,258 @@
+package vault
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	fileVersion = 1
+	// kdfPassphrase 口令派生密钥（PBKDF2-HMAC-SHA256）
+	kdfPassphrase = "pbkdf2-sha256"
+	// kdfKeyFile 密钥文件派生密钥（HKDF-SHA256）
+	kdfKeyFile = "hkdf-sha256"
+	// pbkdf2Iterations PBKDF2 迭代次数
+	pbkdf2Iterations = 600000
+	// checkName 用于校验密钥是否正确的内置条目
+	checkName  = "\x00check"
+	checkValue = "auto-checkin-vault"
+)
+
+// ErrNotFound 凭据不存在
+var ErrNotFound = errors.New("凭据不存在")
+
+// Key 凭据库密钥来源，Passphrase 与 KeyFile 二选一
+type Key struct {
+	Passphrase string
+	KeyFile    string
+}
+
+type entry struct {
+	Nonce   []byte    `json:"nonce"`
+	Data    []byte    `json:"data"`
+	Updated time.Time `json:"updated"`
+}
+
+type fileFormat struct {
+	Version    int              `json:"version"`
+	KDF        string           `json:"kdf"`
+	Salt       []byte           `json:"salt"`
+	Iterations int              `json:"iterations,omitempty"`
+	Check      entry            `json:"check"`
+	Entries    map[string]entry `json:"entries"`
+}
+
+// Vault 基于 AES-256-GCM 的本地加密凭据库，每个条目单独加密并以条目名作为附加数据
+type Vault struct {
+	path string
+	mu   sync.Mutex
+	aead cipher.AEAD
+	file fileFormat
+}
+
+// Open 打开凭据库，文件不存在时创建新的空凭据库（调用 Save 后写入磁盘）
+func Open(path string, key Key) (*Vault, error) {
+	v := &Vault{path: path}
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		v.file = fileFormat{Version: fileVersion, Entries: make(map[string]entry)}
+		v.file.Salt = make([]byte, 16)
+		if _, err := rand.Read(v.file.Salt); err != nil {
+			return nil, err
+		}
+		if key.KeyFile != "" {
+			v.file.KDF = kdfKeyFile
+		} else {
+			v.file.KDF = kdfPassphrase
+			v.file.Iterations = pbkdf2Iterations
+		}
+		if v.aead, err = deriveAEAD(v.file, key); err != nil {
+			return nil, err
+		}
+		if v.file.Check, err = v.seal(checkName, checkValue); err != nil {
+			return nil, err
+		}
+		return v, nil
+	case err != nil:
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &v.file); err != nil {
+		return nil, fmt.Errorf("凭据库文件损坏: %v", err)
+	}
+	if v.file.Version != fileVersion {
+		return nil, fmt.Errorf("不支持的凭据库版本: %d", v.file.Version)
+	}
+	if v.file.Entries == nil {
+		v.file.Entries = make(map[string]entry)
+	}
+	if v.aead, err = deriveAEAD(v.file, key); err != nil {
+		return nil, err
+	}
+	if check, err := v.open(checkName, v.file.Check); err != nil || check != checkValue {
+		return nil, errors.New("凭据库密钥错误")
+	}
+	return v, nil
+}
+
+// deriveAEAD 按凭据库记录的派生方式生成 AES-GCM 实例
+func deriveAEAD(f fileFormat, key Key) (cipher.AEAD, error) {
+	var k []byte
+	var err error
+	switch f.KDF {
+	case kdfPassphrase:
+		if key.Passphrase == "" {
+			return nil, errors.New("凭据库使用口令加密，但未提供口令")
+		}
+		k, err = pbkdf2.Key(sha256.New, key.Passphrase, f.Salt, f.Iterations, 32)
+	case kdfKeyFile:
+		if key.KeyFile == "" {
+			return nil, errors.New("凭据库使用密钥文件加密，但未提供密钥文件")
+		}
+		secret, readErr := os.ReadFile(key.KeyFile)
+		if readErr != nil {
+			return nil, fmt.Errorf("读取密钥文件失败: %v", readErr)
+		}
+		if len(secret) == 0 {
+			return nil, errors.New("密钥文件为空")
+		}
+		k, err = hkdf.Key(sha256.New, secret, f.Salt, "auto-checkin vault", 32)
+	default:
+		return nil, fmt.Errorf("不支持的密钥派生方式: %s", f.KDF)
+	}
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(k)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func (v *Vault) seal(name, value string) (entry, error) {
+	nonce := make([]byte, v.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return entry{}, err
+	}
+	return entry{
+		Nonce:   nonce,
+		Data:    v.aead.Seal(nil, nonce, []byte(value), []byte(name)),
+		Updated: time.Now(),
+	}, nil
+}
+
+func (v *Vault) open(name string, e entry) (string, error) {
+	plain, err := v.aead.Open(nil, e.Nonce, e.Data, []byte(name))
+	if err != nil {
+		return "", fmt.Errorf("凭据 %s 解密失败: %v", name, err)
+	}
+	return string(plain), nil
+}
+
+// Encrypt 使用凭据库密钥加密任意数据，返回随机 nonce 与密文，name 作为附加数据，解密时必须一致
+func (v *Vault) Encrypt(name string, data []byte) ([]byte, error) {
+	nonce := make([]byte, v.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return v.aead.Seal(nonce, nonce, data, []byte(name)), nil
+}
+
+// Decrypt 解密 Encrypt 加密的数据
+func (v *Vault) Decrypt(name string, data []byte) ([]byte, error) {
+	size := v.aead.NonceSize()
+	if len(data) < size {
+		return nil, fmt.Errorf("%s 解密失败: 数据过短", name)
+	}
+	plain, err := v.aead.Open(nil, data[:size], data[size:], []byte(name))
+	if err != nil {
+		return nil, fmt.Errorf("%s 解密失败: %v", name, err)
+	}
+	return plain, nil
+}
+
+// Get 读取凭据
+func (v *Vault) Get(name string) (string, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	e, ok := v.file.Entries[name]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	return v.open(name, e)
+}
+
+// Set 写入或更新凭据
+func (v *Vault) Set(name, value string) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	e, err := v.seal(name, value)
+	if err != nil {
+		return err
+	}
+	v.file.Entries[name] = e
+	return nil
+}
+
+// Delete 删除凭据
+func (v *Vault) Delete(name string) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if _, ok := v.file.Entries[name]; !ok {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	delete(v.file.Entries, name)
+	return nil
+}
+
+// Item 凭据列表项
+type Item struct {
+	Name    string
+	Updated time.Time
+}
+
+// List 返回所有凭据名称及更新时间，按名称排序
+func (v *Vault) List() []Item {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	items := make([]Item, 0, len(v.file.Entries))
+	for name, e := range v.file.Entries {
+		items = append(items, Item{Name: name, Updated: e.Updated})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Name < items[j].Name
+	})
+	return items
+}
+
+// Save 将凭据库写入磁盘（仅所有者可读写）
+func (v *Vault) Save() error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	data, err := json.MarshalIndent(v.file, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(v.path), 0700); err != nil {
+		return err
+	}
+	tmp := v.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, v.path)
+}
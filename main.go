@@ -5,12 +5,75 @@ import (
 	"auto-checkin/internal/logger"
 	"auto-checkin/internal/notifier"
 	"auto-checkin/internal/scheduler"
+	"context"
+	"flag"
+	"fmt"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
 )
 
+// configFile 配置文件路径，可通过全局参数 --config 指定
+var configFile = "config.json"
+
+const usage = `用法: auto-checkin [--config 配置文件] <命令> [参数]
+
+命令:
+  serve                  以守护进程方式按定时规则签到（默认）
+  run [--only jd,quark] [--force]
+                         立即执行一次签到
+  list-handlers          列出已注册的签到处理器
+  next-runs [-n 5]       查看定时任务接下来的执行时间
+  history                查询签到历史
+  validate [文件]        校验配置文件
+  convert [--force] <源文件> <目标文件>
+                         转换配置文件格式
+  vault                  管理加密凭据库
+
+全局参数:`
+
 func main() {
+	flag.StringVar(&configFile, "config", configFile, "配置文件路径，支持 .json/.yaml/.yml/.toml")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) == 0 {
+		serveCommand(nil)
+		return
+	}
+	switch args[0] {
+	case "serve":
+		serveCommand(args[1:])
+	case "run":
+		runCommand(args[1:])
+	case "list-handlers":
+		listHandlersCommand(args[1:])
+	case "next-runs":
+		nextRunsCommand(args[1:])
+	case "history":
+		historyCommand(args[1:])
+	case "validate":
+		validateCommand(args[1:])
+	case "convert":
+		convertCommand(args[1:])
+	case "vault":
+		vaultCommand(args[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "未知命令: %s\n\n", args[0])
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+// setup 加载配置并初始化日志，返回推送模块
+func setup() *notifier.Notifier {
 	// 加载配置
-	_, err := config.Init("config.json")
+	_, err := config.Init(configFile)
 	if err != nil {
 		log.Fatalf("加载配置失败: %v", err)
 	}
@@ -20,11 +83,27 @@ func main() {
 	if err != nil {
 		log.Fatalf("初始化日志失败: %v", err)
 	}
+	// 初始化推送模块
+	return notifier.New(nil)
+}
+
+// serveCommand 以守护进程方式运行定时签到
+func serveCommand(args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	_ = fs.Parse(args)
+
+	notify := setup()
+	if err := config.ValidateSchedule(config.Get()); err != nil {
+		log.Fatalf("加载配置失败: %v", err)
+	}
 	logger.Log().Info("服务已启动")
 	defer logger.Log().Close()
-	// 初始化推送模块
-	notify := notifier.New()
 	// 初始化定时任务
-	sd := scheduler.New(notify)
-	sd.Start()
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	sd := scheduler.New(notify, nil)
+	if !config.Get().Debug {
+		go watchConfig(ctx, configFile, sd)
+	}
+	sd.Start(ctx)
 }
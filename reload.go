@@ -0,0 +1,38 @@
+package main
+
+import (
+	"auto-checkin/internal/config"
+	"auto-checkin/internal/logger"
+	"auto-checkin/internal/scheduler"
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// watchInterval 配置文件变化检查间隔
+const watchInterval = 5 * time.Second
+
+// watchConfig 监听配置文件变化与 SIGHUP 信号，校验通过后热加载配置
+func watchConfig(ctx context.Context, filename string, sd *scheduler.Scheduler) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	config.Watch(ctx, filename, watchInterval, hup, func() {
+		c, err := config.Load(filename)
+		if err == nil {
+			err = config.ValidateSchedule(c)
+		}
+		if err != nil {
+			logger.Log().Errorf("配置热加载失败，继续使用原配置: %v", err)
+			return
+		}
+		if err := sd.Reload(c); err != nil {
+			logger.Log().Errorf("定时任务重新注册失败，继续使用原配置: %v", err)
+			return
+		}
+		logger.Log().Info("配置已重新加载")
+	})
+}